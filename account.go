@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// NewAccountCmd creates a new account command.
+func NewAccountCmd(b cli.Builder) cli.CliCommand {
+	c := &accountCmd{db: b.DB()}
+	c.CliCommand = b.Build("account", "Manage a dominos account.", c)
+	c.Cmd().Long = `The account command shows the dominos account that apizza is signed in to.
+
+Use 'apizza account login' to sign in. Only the account's session is saved,
+the password is never stored.`
+
+	c.Addcmd(newLoginCmd(b), newLogoutCmd(b), newCardsCmd(b), newRewardsCmd(b))
+	return c
+}
+
+// `apizza account`
+type accountCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+}
+
+func (c *accountCmd) Run(cmd *cobra.Command, args []string) error {
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	c.Printf("%s %s\n", user.FirstName, user.LastName)
+	c.Printf("  email: %s\n", user.Email)
+	c.Printf("  phone: %s\n", user.Phone)
+	if addr := user.DefaultAddress(); addr != nil {
+		c.Printf("  address: %s, %s\n", addr.LineOne(), addr.City())
+	}
+	return nil
+}
+
+func newLoginCmd(b cli.Builder) cli.CliCommand {
+	c := &loginCmd{db: b.DB(), in: os.Stdin}
+	c.CliCommand = b.Build("login <username>", "Sign in to a dominos account.", c)
+	c.Cmd().Long = `Sign in to a dominos account. The password is always prompted for so that
+it does not end up in the shell's history.`
+	return c
+}
+
+// `apizza account login`
+type loginCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+	in io.Reader
+}
+
+func (c *loginCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give the username or email of the account")
+	}
+	c.Printf("password: ")
+	password, err := c.readPassword()
+	if err != nil || password == "" {
+		return errors.New("no password given")
+	}
+	user, err := signInWithPassword(args[0], password)
+	if err != nil {
+		return err
+	}
+	if err = saveSession(user, c.db); err != nil {
+		return err
+	}
+	c.Printf("signed in as %s %s\n", user.FirstName, user.LastName)
+	return nil
+}
+
+// readPassword reads the password without echoing it if the input is a
+// terminal, otherwise the whole line is used as the password.
+func (c *loginCmd) readPassword() (string, error) {
+	if f, ok := c.in.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		password, err := term.ReadPassword(int(f.Fd()))
+		c.Printf("\n")
+		return string(password), err
+	}
+	line, err := bufio.NewReader(c.in).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func newLogoutCmd(b cli.Builder) cli.CliCommand {
+	c := &logoutCmd{db: b.DB()}
+	c.CliCommand = b.Build("logout", "Sign out of the dominos account.", c)
+	return c
+}
+
+// `apizza account logout`
+type logoutCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+}
+
+func (c *logoutCmd) Run(cmd *cobra.Command, args []string) error {
+	return c.db.Delete(data.SessionKey)
+}
+
+// These are replaced in the tests so that the account commands can sign
+// in to a test server.
+var (
+	signInWithPassword = dawg.SignIn
+	signInWithToken    = dawg.SignInWithToken
+)
+
+// signIn signs in with the session saved by 'apizza account login'.
+func signIn(db *cache.DataBase) (*dawg.UserProfile, error) {
+	s, err := data.GetSession(db)
+	if err == data.ErrNoSession {
+		return nil, errors.New("not signed in (see 'apizza account login')")
+	} else if err != nil {
+		return nil, err
+	}
+	user, err := signInWithToken(s)
+	if err != nil {
+		return nil, err
+	}
+	// the access token may have been refreshed
+	return user, saveSession(user, db)
+}
+
+func saveSession(user *dawg.UserProfile, db *cache.DataBase) error {
+	s, err := user.Session()
+	if err != nil {
+		return err
+	}
+	return data.SaveSession(s, db)
+}
@@ -0,0 +1,268 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+// testAccount saves a session and makes the account commands sign in to a
+// test server that uses the mux. The returned function must be called to
+// undo it.
+func testAccount(t *testing.T, db *cache.DataBase, mux *http.ServeMux) func() {
+	srv := httptest.NewServer(mux)
+	mux.HandleFunc("/power/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"CustomerID":"user","FirstName":"Jimmy","LastName":"Smith",
+			"Email":"jimmy@example.com","Phone":"555-555-5555","Status":0}`)
+	})
+	c, err := dawg.NewClient(dawg.WithBaseURL(srv.URL), dawg.WithAuthURL(srv.URL+"/oauth"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = data.SaveSession(&dawg.Session{
+		AccessToken: "access",
+		Type:        "Bearer",
+		Expires:     time.Now().Add(time.Hour),
+	}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signInWithPassword, signInWithToken = c.SignIn, c.SignInWithToken
+	return func() {
+		signInWithPassword, signInWithToken = dawg.SignIn, dawg.SignInWithToken
+		srv.Close()
+	}
+}
+
+func TestAccountCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.Form.Get("refresh_token") != "refresh-1" {
+			t.Errorf("wrong refresh token %q", r.Form.Get("refresh_token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access-2","refresh_token":"refresh-2","token_type":"Bearer","expires_in":3600}`)
+	})
+	defer testAccount(t, r.DataBase, mux)()
+	tests.Check(data.SaveSession(&dawg.Session{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Type:         "Bearer",
+		Expires:      time.Now().Add(-time.Hour),
+	}, r.DataBase))
+
+	cmd := NewAccountCmd(r)
+	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	tests.Compare(t, r.Out.String(), `Jimmy Smith
+  email: jimmy@example.com
+  phone: 555-555-5555
+`)
+	s, err := data.GetSession(r.DataBase)
+	tests.Check(err)
+	if s.AccessToken != "access-2" || s.RefreshToken != "refresh-2" || s.Expired() {
+		t.Errorf("the refreshed session was not saved: %+v", s)
+	}
+
+	logout := newLogoutCmd(r)
+	tests.Check(logout.Run(logout.Cmd(), []string{}))
+	if _, err = data.GetSession(r.DataBase); err != data.ErrNoSession {
+		t.Error("logout should remove the session")
+	}
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{}), "should not be signed in after logging out")
+}
+
+func TestLoginCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.Form.Get("username") != "jimmy@example.com" || r.Form.Get("password") != "correct horse" {
+			t.Errorf("wrong credentials: %q %q", r.Form.Get("username"), r.Form.Get("password"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","token_type":"Bearer","expires_in":3600}`)
+	})
+	defer testAccount(t, r.DataBase, mux)()
+	tests.Check(r.DataBase.Delete(data.SessionKey))
+
+	cmd := newLoginCmd(r).(*loginCmd)
+	if cmd.Cmd().Flags().Lookup("password") != nil {
+		t.Error("the password should not be a flag")
+	}
+	cmd.in = strings.NewReader("correct horse\n")
+	tests.Check(cmd.Run(cmd.Cmd(), []string{"jimmy@example.com"}))
+	tests.Compare(t, r.Out.String(), "password: signed in as Jimmy Smith\n")
+	s, err := data.GetSession(r.DataBase)
+	tests.Check(err)
+	if s.AccessToken != "access" || s.RefreshToken != "refresh" {
+		t.Errorf("wrong session saved: %+v", s)
+	}
+
+	cmd.in = strings.NewReader("")
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{"jimmy@example.com"}), "expected an error without a password")
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{}), "expected an error without a username")
+}
+
+func TestCardsCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	var calls []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/customer/user/card", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == "GET" {
+			fmt.Fprint(w, `[{"id":"1","nickName":"work","cardType":"VISA","lastFour":"1111",
+				"expirationMonth":1,"expirationYear":2030,"isDefault":true}]`)
+			return
+		}
+		body := struct {
+			NickName  string
+			IsDefault bool
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+		if body.NickName != "home" || !body.IsDefault {
+			t.Errorf("wrong card saved: %+v", body)
+		}
+		fmt.Fprint(w, `{"id":"2","nickName":"home","lastFour":"1111"}`)
+	})
+	mux.HandleFunc("/power/customer/user/card/1", func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Method == "PUT" {
+			body := struct{ IsDefault bool }{true}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+			if body.IsDefault {
+				t.Error("the card should no longer be the default")
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	defer testAccount(t, r.DataBase, mux)()
+
+	cards := newCardsCmd(r)
+	tests.Check(cards.Run(cards.Cmd(), []string{}))
+	tests.StrEq(r.Out.String(), "work: VISA ending in 1111, expires 01/30 (default)\n", "wrong cards output")
+
+	r.Out.Reset()
+	add := newAddCardCmd(r)
+	tests.Check(add.Cmd().ParseFlags([]string{"--number=4111111111111111", "--expiration=01/30", "--cvv=123", "--zip=12345", "--default"}))
+	tests.Check(add.Run(add.Cmd(), []string{"home"}))
+	tests.StrEq(r.Out.String(), "saved card 'home'\n", "wrong output")
+	tests.Exp(add.Run(add.Cmd(), []string{}), "expected an error without a nickname")
+
+	r.Out.Reset()
+	update := newUpdateCardCmd(r)
+	tests.Check(update.Cmd().ParseFlags([]string{"--default=false"}))
+	tests.Check(update.Run(update.Cmd(), []string{"WORK"}))
+	if strings.Contains(r.Out.String(), "(default)") {
+		t.Errorf("the card should not be the default anymore: %q", r.Out.String())
+	}
+
+	r.Out.Reset()
+	del := newDeleteCardCmd(r)
+	tests.Check(del.Run(del.Cmd(), []string{"work"}))
+	tests.StrEq(r.Out.String(), "deleted card 'work'\n", "wrong output")
+	tests.Exp(del.Run(del.Cmd(), []string{"nothere"}), "expected an error for a missing card")
+
+	expected := []string{
+		"GET /power/customer/user/card",
+		"POST /power/customer/user/card",
+		"GET /power/customer/user/card",
+		"PUT /power/customer/user/card/1",
+		"GET /power/customer/user/card",
+		"DELETE /power/customer/user/card/1",
+		"GET /power/customer/user/card",
+	}
+	if strings.Join(calls, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("wrong requests:\n%s", strings.Join(calls, "\n"))
+	}
+}
+
+func TestPrintRewards(t *testing.T) {
+	loyalty := &dawg.CustomerLoyalty{
+		VestedPointBalance:  80,
+		PendingPointBalance: "10",
+		LoyaltyCoupons: []dawg.LoyaltyCoupon{
+			{CouponCode: "8629", PointValue: 60, LimitPerOrder: "1"},
+			{CouponCode: "9194", PointValue: 120},
+		},
+	}
+	buf := new(bytes.Buffer)
+	printRewards(buf, loyalty, false)
+	tests.Compare(t, buf.String(), `points: 80 (10 pending)
+rewards:
+  8629: 60 points, 1 per order
+`)
+	buf.Reset()
+	printRewards(buf, loyalty, true)
+	tests.Compare(t, buf.String(), `points: 80 (10 pending)
+rewards:
+  8629: 60 points, 1 per order
+  9194: 120 points
+`)
+	buf.Reset()
+	loyalty.VestedPointBalance, loyalty.PendingPointBalance = 20, "0"
+	printRewards(buf, loyalty, false)
+	tests.Compare(t, buf.String(), "points: 20\nNot enough points for any rewards.\n")
+}
+
+func TestRewardsCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/customer/user/loyalty", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"VestedPointBalance": 80, "LoyaltyCoupons": [
+			{"CouponCode": "8629", "PointValue": 60, "LimitPerOrder": "1"}]}`)
+	})
+	mux.HandleFunc("/power/validate-order", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Status": 0, "Order": {}}`)
+	})
+	defer testAccount(t, r.DataBase, mux)()
+	raw, err := json.Marshal(&dawg.Order{OrderName: "pizza", ServiceMethod: dawg.Carryout})
+	tests.Check(err)
+	tests.Check(r.DataBase.Put(data.OrderPrefix+"pizza", raw))
+
+	cmd := newRewardsCmd(r)
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--apply=8629"}))
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{}), "expected an error for --apply without --order")
+
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--order=pizza"}))
+	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	tests.Compare(t, r.Out.String(), "order successfully updated.\n20 points left after 'pizza'\n")
+	o, err := data.GetOrder("pizza", r.DataBase)
+	tests.Check(err)
+	if len(o.Coupons) != 1 || o.Coupons[0].Code != "8629" || o.CustomerID != "user" {
+		t.Errorf("the loyalty coupon was not added: %+v", o)
+	}
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{}), "the coupon should only be allowed once per order")
+}
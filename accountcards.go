@@ -0,0 +1,193 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func newCardsCmd(b cli.Builder) cli.CliCommand {
+	c := &cardsCmd{db: b.DB()}
+	c.CliCommand = b.Build("cards", "Manage the cards saved to the dominos account.", c)
+	c.Cmd().Long = `The cards command lists the cards saved to the signed in account.
+
+Saved cards are referred to by their nickname, which is also how they are
+used to pay for an order (see 'apizza order --pay saved:<name>').`
+
+	c.Addcmd(newAddCardCmd(b), newUpdateCardCmd(b), newDeleteCardCmd(b))
+	return c
+}
+
+// `apizza account cards`
+type cardsCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+}
+
+func (c *cardsCmd) Run(cmd *cobra.Command, args []string) error {
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	cards, err := user.Cards()
+	if err != nil {
+		return err
+	}
+	if len(cards) == 0 {
+		c.Println("No saved cards.")
+		return nil
+	}
+	for _, card := range cards {
+		printCard(c.Output(), card)
+	}
+	return nil
+}
+
+func printCard(w io.Writer, c *dawg.UserCard) {
+	fmt.Fprintf(w, "%s: %s ending in %s, expires %02d/%d", c.NickName, c.CardType,
+		c.LastFour, c.ExpirationMonth, c.ExpirationYear%100)
+	if c.IsDefault {
+		fmt.Fprint(w, " (default)")
+	}
+	if c.IsExpired {
+		fmt.Fprint(w, " (expired)")
+	}
+	fmt.Fprintln(w)
+}
+
+func newAddCardCmd(b cli.Builder) cli.CliCommand {
+	c := &addCardCmd{db: b.DB()}
+	c.CliCommand = b.Build("add <name>", "Save a new card to the account.", c)
+	c.Cmd().Long = `Save a new card to the account with a nickname.
+
+The card number and expiration date default to the card in the config file.`
+	flags := c.Flags()
+	flags.StringVar(&c.number, "number", "", "the card number")
+	flags.StringVar(&c.expiration, "expiration", "", "the card's expiration date")
+	flags.IntVar(&c.cvv, "cvv", 0, "the card's cvv number")
+	flags.StringVar(&c.zip, "zip", "", "the zip code of the card's billing address")
+	flags.BoolVar(&c.isDefault, "default", false, "make the card the account's default card")
+	return c
+}
+
+// `apizza account cards add`
+type addCardCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+
+	number     string
+	expiration string
+	cvv        int
+	zip        string
+	isDefault  bool
+}
+
+func (c *addCardCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give a nickname for the card")
+	}
+	if c.cvv == 0 {
+		return errors.New("must have cvv number. (see --cvv)")
+	}
+	num := eitherOr(c.number, config.GetString("card.number"))
+	exp := eitherOr(c.expiration, config.GetString("card.expiration"))
+	if num == "" {
+		return errors.New("no card number given")
+	}
+	card := dawg.NewCard(num, exp, c.cvv)
+	if card == nil {
+		return errors.New("bad card expiration date format")
+	}
+
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	if _, err = user.AddCard(card, args[0], c.zip, c.isDefault); err != nil {
+		return err
+	}
+	c.Printf("saved card '%s'\n", args[0])
+	return nil
+}
+
+func newUpdateCardCmd(b cli.Builder) cli.CliCommand {
+	c := &updateCardCmd{db: b.DB()}
+	c.CliCommand = b.Build("update <name>", "Change the details of a saved card.", c)
+	flags := c.Flags()
+	flags.StringVar(&c.name, "name", "", "give the card a new nickname")
+	flags.StringVar(&c.zip, "zip", "", "change the zip code of the card's billing address")
+	flags.BoolVar(&c.isDefault, "default", false, "make the card the account's default card")
+	return c
+}
+
+// `apizza account cards update`
+type updateCardCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+
+	name      string
+	zip       string
+	isDefault bool
+}
+
+func (c *updateCardCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give the nickname of the card to update")
+	}
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	card, err := user.FindCard(args[0])
+	if err != nil {
+		return err
+	}
+	card.NickName = eitherOr(c.name, card.NickName)
+	card.BillingZip = eitherOr(c.zip, card.BillingZip)
+	if cmd.Flags().Changed("default") {
+		card.IsDefault = c.isDefault
+	}
+	if err = user.UpdateCard(card); err != nil {
+		return err
+	}
+	printCard(c.Output(), card)
+	return nil
+}
+
+func newDeleteCardCmd(b cli.Builder) cli.CliCommand {
+	c := &deleteCardCmd{db: b.DB()}
+	c.CliCommand = b.Build("delete <name>", "Remove a saved card from the account.", c)
+	c.Cmd().Aliases = []string{"rm"}
+	return c
+}
+
+// `apizza account cards delete`
+type deleteCardCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+}
+
+func (c *deleteCardCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return errors.New("give the nickname of the card to delete")
+	}
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	card, err := user.FindCard(args[0])
+	if err != nil {
+		return err
+	}
+	if err = user.DeleteCard(card); err != nil {
+		return err
+	}
+	c.Printf("deleted card '%s'\n", card.NickName)
+	return nil
+}
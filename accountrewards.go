@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+func newRewardsCmd(b cli.Builder) cli.CliCommand {
+	c := &rewardsCmd{db: b.DB()}
+	c.CliCommand = b.Build("rewards", "Spend loyalty points on coupons.", c)
+	c.Cmd().Long = `The rewards command shows the account's loyalty points and the coupons
+that they can buy.
+
+Use --apply with --order to spend points on a coupon for an order in the cart.
+The order will then be sent as the signed in account.`
+
+	flags := c.Flags()
+	flags.StringVar(&c.apply, "apply", "", "the code of a loyalty coupon to add to an order")
+	flags.StringVarP(&c.order, "order", "o", "", "the cart order that the coupon is added to")
+	flags.BoolVarP(&c.all, "all", "a", false, "show every loyalty coupon, even ones that cost too many points")
+	return c
+}
+
+// `apizza account rewards`
+type rewardsCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+
+	apply string
+	order string
+	all   bool
+}
+
+func (c *rewardsCmd) Run(cmd *cobra.Command, args []string) error {
+	if c.apply != "" && c.order == "" {
+		return errors.New("give the name of the order to apply the coupon to (see --order)")
+	}
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+	loyalty, err := user.Loyalty()
+	if err != nil {
+		return err
+	}
+
+	if c.apply == "" {
+		printRewards(c.Output(), loyalty, c.all)
+		return nil
+	}
+	order, err := data.GetOrder(c.order, c.db)
+	if err != nil {
+		return err
+	}
+	if err = user.RedeemLoyaltyCoupon(order, c.apply); err != nil {
+		return err
+	}
+	if err = data.SaveOrder(order, c.Output(), c.db); err != nil {
+		return err
+	}
+	c.Printf("%d points left after '%s'\n",
+		loyalty.VestedPointBalance-loyalty.PointsUsed(order), order.Name())
+	return nil
+}
+
+func printRewards(w io.Writer, l *dawg.CustomerLoyalty, all bool) {
+	fmt.Fprintf(w, "points: %d", l.VestedPointBalance)
+	if l.PendingPointBalance != "" && l.PendingPointBalance != "0" {
+		fmt.Fprintf(w, " (%s pending)", l.PendingPointBalance)
+	}
+	fmt.Fprintln(w)
+
+	coupons := l.Affordable()
+	if all {
+		coupons = coupons[:0]
+		for i := range l.LoyaltyCoupons {
+			coupons = append(coupons, &l.LoyaltyCoupons[i])
+		}
+	}
+	if len(coupons) == 0 {
+		fmt.Fprintln(w, "Not enough points for any rewards.")
+		return
+	}
+	fmt.Fprintln(w, "rewards:")
+	for _, c := range coupons {
+		fmt.Fprintf(w, "  %s: %d points", c.CouponCode, c.PointValue)
+		if limit := c.Limit(); limit > 0 {
+			fmt.Fprintf(w, ", %d per order", limit)
+		}
+		fmt.Fprintln(w)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/cache"
 	"github.com/spf13/cobra"
 )
@@ -15,15 +16,22 @@ import (
 // NewAddAddressCmd creates the 'add-address' command.
 func NewAddAddressCmd(b cli.Builder, in io.Reader) cli.CliCommand {
 	c := &addAddressCmd{
-		db:  b.DB(),
-		in:  in,
-		new: false,
+		db:           b.DB(),
+		in:           in,
+		new:          false,
+		validateAddr: dawg.ValidateAddress,
 	}
 	c.CliCommand = b.Build("address", "Add a new named address to the internal storage.", c)
 	cmd := c.Cmd()
 	cmd.Long = `The address command is where user addresses are managed. Addresses added with
 the '--new' flag are put into the program's internal storage. To set one of theses
-addresses as the program default set the appropriate config file option (default-address-name).`
+addresses as the program default set the appropriate config file option (default-address-name).
+
+A unit (apartment, suite, etc.) and delivery instructions can also be given
+for each address, they are sent with every delivery order to that address.
+
+New addresses are checked with dominos before they are saved, if dominos
+normalizes the address differently then the normalized form is offered instead.`
 	cmd.Aliases = []string{"addr"}
 	cmd.Flags().BoolVarP(&c.new, "new", "n", c.new, "add a new address")
 	cmd.Flags().StringVarP(&c.delete, "delete", "d", "", "delete an address")
@@ -37,6 +45,9 @@ type addAddressCmd struct {
 	in     io.Reader
 	new    bool
 	delete string
+
+	// validateAddr checks new addresses with dominos.
+	validateAddr func(dawg.Address) (*dawg.StreetAddr, dawg.Granularity, error)
 }
 
 func (a *addAddressCmd) Run(cmd *cobra.Command, args []string) error {
@@ -66,6 +77,9 @@ func (a *addAddressCmd) Run(cmd *cobra.Command, args []string) error {
 		}
 
 		a.Printf("%s:\n  %s\n", key, obj.AddressFmtIndent(addr, 2))
+		if addr.DeliveryInstructions != "" {
+			a.Printf("  instructions: %s\n", addr.DeliveryInstructions)
+		}
 	}
 	return nil
 }
@@ -104,6 +118,19 @@ func (a *addAddressCmd) newAddress() error {
 		return err
 	}
 
+	a.Printf("Unit (e.g. 'Apt 4', optional): ")
+	if unit := r.readoptional(); unit != "" {
+		var ok bool
+		addr.UnitType, addr.UnitNumber, ok = dawg.ParseUnit(unit)
+		if !ok {
+			return fmt.Errorf("could not understand the unit '%s'", unit)
+		}
+	}
+	a.Printf("Delivery Instructions (optional): ")
+	addr.DeliveryInstructions = r.readoptional()
+
+	a.validate(&addr, &r)
+
 	fmt.Fprint(a.Output(), name, ":\n", addr, "\n")
 	raw, err := obj.AsGob(&addr)
 	if err != nil {
@@ -112,6 +139,28 @@ func (a *addAddressCmd) newAddress() error {
 	return a.db.WithBucket("addresses").Put(name, raw)
 }
 
+// validate checks the address with dominos and offers to replace it with
+// the address that dominos normalized it to.
+func (a *addAddressCmd) validate(addr *obj.Address, r *reader) {
+	norm, granularity, err := a.validateAddr(addr)
+	if err != nil {
+		a.Printf("could not validate the address: %v\n", err)
+		return
+	}
+	if granularity != dawg.GranularityExact {
+		a.Printf("warning: dominos could only match the address to the %s\n",
+			strings.ToLower(string(granularity)))
+	}
+	if strings.EqualFold(obj.AddressFmt(norm), obj.AddressFmt(addr)) {
+		return
+	}
+	a.Printf("dominos found:\n  %s\nuse this address instead? [y/N] ", obj.AddressFmtIndent(norm, 2))
+	answer, err := r.readline()
+	if err == nil && strings.HasPrefix(strings.ToLower(answer), "y") {
+		*addr = *obj.FromAddress(norm)
+	}
+}
+
 func (r *reader) readline() (string, error) {
 	lineone, err := r.scanner.ReadString('\n')
 	if err != nil {
@@ -119,3 +168,9 @@ func (r *reader) readline() (string, error) {
 	}
 	return strings.Trim(lineone, "\n \t\r"), nil
 }
+
+// readoptional reads a line that can be left out of the input.
+func (r *reader) readoptional() string {
+	line, _ := r.scanner.ReadString('\n')
+	return strings.Trim(line, "\n \t\r")
+}
@@ -55,3 +55,50 @@ Washington DC, 20500`,
 		t.Error("addr should be empty")
 	}
 }
+
+func TestAddressUnit(t *testing.T) {
+	tests.InitHelpers(t)
+	a := &Address{
+		Street: "600 Mountain Ave", CityName: "New Providence",
+		State: "NJ", Zipcode: "07974", UnitType: "STE", UnitNumber: "5",
+		DeliveryInstructions: "front desk",
+	}
+	tests.StrEq(AddressFmt(a), "600 Mountain Ave STE 5\nNew Providence, NJ 07974", "wrong address format")
+	a.UnitType = ""
+	tests.StrEq(AddressFmt(a), "600 Mountain Ave #5\nNew Providence, NJ 07974", "wrong address format")
+
+	street := dawg.StreetAddrFromAddress(a)
+	tests.StrEq(street.UnitNumber, "5", "wrong unit number")
+	tests.StrEq(street.DeliveryInstructions, "front desk", "wrong delivery instructions")
+	back := FromAddress(street)
+	if *back != *a {
+		t.Errorf("address changed after conversion: got %+v, want %+v", back, a)
+	}
+
+	if !street.Equal(a) {
+		t.Error("addresses should be equal")
+	}
+	a.UnitNumber = "6"
+	if street.Equal(a) {
+		t.Error("addresses with different units should not be equal")
+	}
+	a.UnitNumber = "5"
+	a.DeliveryInstructions = "back door"
+	if street.Equal(a) {
+		t.Error("addresses with different delivery instructions should not be equal")
+	}
+}
+
+func TestAddressPostalCode(t *testing.T) {
+	tests.InitHelpers(t)
+	for code, exp := range map[string]string{
+		"20500":      "20500",
+		"20500-1234": "20500-1234",
+		"m5h 2n2":    "M5H 2N2",
+		"2050":       "",
+		"bad code":   "",
+	} {
+		a := &Address{Zipcode: code}
+		tests.StrEq(a.Zip(), exp, "wrong zip for %q", code)
+	}
+}
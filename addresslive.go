@@ -1,45 +1,354 @@
 package dawg
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
-)
-
-var (
-	addressRegex = regexp.MustCompile(
-		fmt.Sprintf(`^(?P<%s>[0-9]{1,4})\s(?P<%s>[A-Za-z ]+\.|\n).?\n?\s*(?P<%s>[A-Za-z ]+),\s(?P<%s>[A-Z]{2})\s(?P<%s>[0-9]{5}).*$`,
-			"street_num", "street", "city", "state", "zipcode"),
-	)
+	"unicode"
 )
 
 // ParseAddress will parse a raw address and return an address object.
-// This method is prone to all the mishaps that arise when trying to work
-// with addresses, it may not work for all cases.
+//
+// The street and city can be separated by a comma or a new line, for example
+// "123 Main St. Apt 4, Springfield, IL 62701". If they are not, the street
+// must end with a street type such as "St" or "Ave" so that the two can be
+// told apart. Unit designators (Apt, Suite, Unit, #, etc.) and ZIP+4 codes
+// are supported.
+//
+// An *AddressError naming the part of the address that could not be
+// parsed is returned on failure.
+//
+// Addresses are parsed using the format of the default market, see SetMarket
+// and Market.ParseAddress.
 func ParseAddress(raw string) (*StreetAddr, error) {
-	parsed, err := parse([]byte(raw))
-	if err != nil {
-		return nil, err
+	return DefaultMarket().ParseAddress(raw)
+}
+
+// ParseAddress parses an address using the market's postal code format and
+// regions. See the ParseAddress function.
+func (m *Market) ParseAddress(raw string) (*StreetAddr, error) {
+	segs := addressSegments(raw)
+	if len(segs) == 0 {
+		return nil, &AddressError{Part: "address", Input: raw}
+	}
+	addr := &StreetAddr{}
+
+	// the state and zip code are always at the end
+	last := segs[len(segs)-1]
+	zip, n := m.postal(last)
+	if n == 0 {
+		return nil, &AddressError{Part: m.PostalName, Input: raw}
 	}
-	addr := &StreetAddr{
-		StreetNum:  string(parsed[1]),
-		Street:     string(parsed[1]) + " " + string(parsed[2]),
-		StreetName: string(parsed[2]),
-		CityName:   string(parsed[3]),
-		State:      string(parsed[4]),
-		Zipcode:    string(parsed[5]),
+	addr.Zipcode = zip
+	segs = trimSegments(segs, n)
+	if len(segs) == 0 {
+		return nil, &AddressError{Part: m.RegionName, Input: raw}
 	}
+	last = segs[len(segs)-1]
+	state := strings.ToUpper(strings.TrimSuffix(last[len(last)-1], "."))
+	if !m.IsRegion(state) {
+		return nil, &AddressError{Part: m.RegionName, Input: raw}
+	}
+	addr.State = state
+	segs = trimSegments(segs, 1)
+	if len(segs) == 0 {
+		return nil, &AddressError{Part: "street", Input: raw}
+	}
+
+	var street, city []string
+	if len(segs) == 1 {
+		// no comma so the street type marks the end of the street
+		street, city = splitStreet(segs[0])
+		if street == nil {
+			return nil, &AddressError{Part: "street", Input: raw}
+		}
+	} else {
+		street, city = segs[0], segs[len(segs)-1]
+		for _, seg := range segs[1 : len(segs)-1] {
+			street = append(street, seg...)
+		}
+	}
+	if len(city) == 0 {
+		return nil, &AddressError{Part: "city", Input: raw}
+	}
+	addr.CityName = strings.Join(city, " ")
+
+	if !isStreetNum(street[0]) {
+		return nil, &AddressError{Part: "street number", Input: raw}
+	}
+	addr.StreetNum = street[0]
+	name := street[1:]
+	for i, tok := range name {
+		// the first word is always part of the street name and so is a
+		// word like "lot" or "floor" that comes before a street type
+		if i == 0 || !isUnitDesignator(tok) ||
+			(i+1 < len(name) && streetTypes[normalizeWord(name[i+1])]) {
+			continue
+		}
+		typ, num, n := parseUnit(name[i:])
+		if n == 0 || i+n != len(name) {
+			return nil, &AddressError{Part: "unit", Input: raw}
+		}
+		addr.UnitType, addr.UnitNumber = typ, num
+		name = name[:i]
+		break
+	}
+	if len(name) == 0 {
+		return nil, &AddressError{Part: "street", Input: raw}
+	}
+	addr.StreetName = strings.Join(name, " ")
+	addr.Street = addr.StreetNum + " " + addr.StreetName
 	return addr, nil
 }
 
-func parse(raw []byte) ([][]byte, error) {
-	res := addressRegex.FindAllSubmatch(raw, -1)
-	if len(res) > 0 {
-		return res[0], nil
+// AddressError is returned when an address cannot be parsed.
+type AddressError struct {
+	// Part is the part of the address that could not be parsed
+	// ("street number", "street", "unit", "city", "state", or "zip code").
+	// Markets outside the US may use their own names for the state and zip
+	// code, like "province" and "postal code". It is "address" if the
+	// input was empty.
+	Part  string
+	Input string
+}
+
+func (e *AddressError) Error() string {
+	if e.Part == "address" {
+		return "empty address"
+	}
+	return fmt.Sprintf("could not parse the %s of address %q", e.Part, e.Input)
+}
+
+// addressSegments splits an address into comma or line separated segments
+// of words.
+func addressSegments(raw string) [][]string {
+	lines := strings.FieldsFunc(raw, func(r rune) bool {
+		return r == ',' || r == '\n' || r == '\r'
+	})
+	segs := make([][]string, 0, len(lines))
+	for _, line := range lines {
+		if words := strings.Fields(line); len(words) > 0 {
+			segs = append(segs, words)
+		}
+	}
+	return segs
+}
+
+// trimSegments removes n words from the end of the last segment.
+func trimSegments(segs [][]string, n int) [][]string {
+	last := segs[len(segs)-1]
+	if len(last) <= n {
+		return segs[:len(segs)-1]
+	}
+	segs[len(segs)-1] = last[:len(last)-n]
+	return segs
+}
+
+// splitStreet splits a street and city that are not separated by a comma.
+// The street ends after the first street type that follows the street
+// number and name, including any directional suffix and unit.
+func splitStreet(words []string) (street, city []string) {
+	for i := 2; i < len(words); i++ {
+		if !streetTypes[normalizeWord(words[i])] {
+			continue
+		}
+		end := i + 1
+		if end < len(words) && directionals[normalizeWord(words[end])] {
+			end++
+		}
+		if end < len(words) && isUnitDesignator(words[end]) {
+			_, _, n := parseUnit(words[end:])
+			end += n
+		}
+		return words[:end], words[end:]
+	}
+	return nil, nil
+}
+
+// ParseUnit parses a unit like "Apt 4", "Suite 200", or "#4" and returns the
+// standard unit type ("APT", "STE", "#") and the unit number. A unit number
+// on its own is returned without a type. False is returned if the text is
+// not a unit.
+func ParseUnit(unit string) (typ, num string, ok bool) {
+	words := strings.Fields(unit)
+	if len(words) == 1 && isUnitNumber(words[0]) {
+		return "", words[0], true
+	}
+	if len(words) == 0 {
+		return "", "", false
+	}
+	typ, num, n := parseUnit(words)
+	return typ, num, n > 0 && n == len(words)
+}
+
+// parseUnit parses a unit designator and number from the beginning of a list
+// of words and returns the number of words used. Zero is returned if the
+// words are not a unit.
+func parseUnit(words []string) (typ, num string, n int) {
+	first := words[0]
+	if strings.HasPrefix(first, "#") && len(first) > 1 {
+		if !isUnitNumber(first[1:]) {
+			return "", "", 0
+		}
+		return "#", first[1:], 1
+	}
+	if first == "#" {
+		typ = "#"
+	} else if typ = unitTypes[normalizeWord(first)]; typ == "" {
+		return "", "", 0
+	}
+	if len(words) < 2 {
+		return "", "", 0
+	}
+	num = strings.TrimPrefix(words[1], "#")
+	if !isUnitNumber(num) {
+		return "", "", 0
+	}
+	return typ, num, 2
+}
+
+// isUnitNumber returns true if a word can be a unit number. Unit numbers
+// are a single letter ("B") or letters, digits, and dashes with at least one
+// digit ("4", "12C", "2-101").
+func isUnitNumber(word string) bool {
+	if len(word) == 1 && unicode.IsLetter(rune(word[0])) {
+		return true
+	}
+	return strings.IndexFunc(word, unicode.IsDigit) >= 0 &&
+		strings.IndexFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+		}) < 0
+}
+
+func isUnitDesignator(word string) bool {
+	return strings.HasPrefix(word, "#") || unitTypes[normalizeWord(word)] != ""
+}
+
+func isStreetNum(word string) bool {
+	digits := 0
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			break
+		}
+		digits++
+	}
+	if digits == 0 {
+		return false
+	}
+	// allow for numbers like "12A" or "123-45"
+	return strings.IndexFunc(word, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	}) < 0
+}
+
+func parseZip(word string) (string, bool) {
+	digits := strings.Replace(word, "-", "", 1)
+	for _, r := range digits {
+		if r < '0' || r > '9' {
+			return "", false
+		}
+	}
+	switch {
+	case len(digits) == 5 && len(word) == 5:
+		return word, true
+	case len(digits) == 9 && (len(word) == 9 || word[5] == '-'):
+		return digits[:5] + "-" + digits[5:], true
+	}
+	return "", false
+}
+
+func isStateCode(s string) bool {
+	if len(s) != 2 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeWord(word string) string {
+	return strings.ToLower(strings.TrimSuffix(word, "."))
+}
+
+var (
+	streetTypes = map[string]bool{
+		"st": true, "street": true, "ave": true, "av": true, "avenue": true,
+		"rd": true, "road": true, "blvd": true, "boulevard": true,
+		"dr": true, "drive": true, "ln": true, "lane": true, "ct": true,
+		"court": true, "pl": true, "place": true, "way": true, "ter": true,
+		"terrace": true, "cir": true, "circle": true, "pkwy": true,
+		"parkway": true, "hwy": true, "highway": true, "trl": true,
+		"trail": true, "sq": true, "square": true, "loop": true, "pike": true,
+		"aly": true, "alley": true, "xing": true, "crossing": true,
+		"plz": true, "plaza": true, "expy": true, "expressway": true,
+		"fwy": true, "freeway": true, "tpke": true, "turnpike": true,
+		"cv": true, "cove": true, "row": true, "run": true, "path": true,
+		"walk": true, "cres": true, "crescent": true, "gate": true,
+		"line": true, "sideroad": true, "conc": true, "concession": true,
+	}
+
+	directionals = map[string]bool{
+		"n": true, "s": true, "e": true, "w": true,
+		"ne": true, "nw": true, "se": true, "sw": true,
+	}
+
+	// unitTypes maps unit designators to their standard abbreviation.
+	unitTypes = map[string]string{
+		"apt": "APT", "apartment": "APT", "ste": "STE", "suite": "STE",
+		"unit": "UNIT", "rm": "RM", "room": "RM", "fl": "FL", "floor": "FL",
+		"bldg": "BLDG", "building": "BLDG", "lot": "LOT", "trlr": "TRLR",
+		"dept": "DEPT", "spc": "SPC", "space": "SPC",
+	}
+)
+
+// Granularity describes how closely dominos was able to match an address.
+type Granularity string
+
+// These are some of the granularities that dominos uses when matching an
+// address. Anything other than GranularityExact means that dominos could not
+// find the exact building.
+const (
+	GranularityExact  Granularity = "Exact"
+	GranularityStreet Granularity = "Street"
+	GranularityCity   Granularity = "City"
+)
+
+// ValidateAddress will send an address to the dominos store locator and
+// return the address as dominos normalized it along with how closely dominos
+// was able to match it.
+func ValidateAddress(addr Address) (*StreetAddr, Granularity, error) {
+	return ValidateAddressContext(context.Background(), addr)
+}
+
+// ValidateAddressContext is the same as ValidateAddress but with a context.
+func ValidateAddressContext(ctx context.Context, addr Address) (*StreetAddr, Granularity, error) {
+	return validateAddress(ctx, orderClient, addr)
+}
+
+func validateAddress(ctx context.Context, c *client, addr Address) (*StreetAddr, Granularity, error) {
+	if addr == nil {
+		return nil, "", errors.New("no address")
+	}
+	locs, err := findNearbyStores(ctx, c, addr, Delivery)
+	if err != nil {
+		return nil, "", err
+	}
+	if locs == nil || locs.Address == nil {
+		return nil, "", errors.New("dominos could not find the address")
+	}
+	norm := locs.Address
+	if u, ok := addr.(UnitAddress); ok {
+		if norm.UnitNumber == "" {
+			norm.UnitType, norm.UnitNumber = u.Unit()
+		}
+		norm.DeliveryInstructions = u.Instructions()
 	}
-	return nil, errors.New("could not parse address string")
+	return norm, Granularity(locs.Granularity), nil
 }
 
 // Address is a guid for how addresses should be used as input
@@ -50,7 +359,21 @@ type Address interface {
 	Zip() string
 }
 
-var _ Address = (*StreetAddr)(nil)
+// UnitAddress is an Address that can have an apartment or suite number and
+// delivery instructions. These are kept when converting an address that
+// implements UnitAddress, see StreetAddrFromAddress.
+type UnitAddress interface {
+	Address
+	// Unit returns the unit type (APT, STE, etc.) and the unit number.
+	Unit() (typ, number string)
+	// Instructions returns the instructions for the delivery driver.
+	Instructions() string
+}
+
+var (
+	_ Address     = (*StreetAddr)(nil)
+	_ UnitAddress = (*StreetAddr)(nil)
+)
 
 // StreetAddr represents a street address
 type StreetAddr struct {
@@ -67,6 +390,14 @@ type StreetAddr struct {
 	State    string `json:"Region"`
 	Zipcode  string `json:"PostalCode"`
 
+	// UnitType is the kind of unit (APT, STE, etc.) and UnitNumber is the
+	// apartment or suite number within the building.
+	UnitType   string `json:"UnitType"`
+	UnitNumber string `json:"UnitNumber"`
+
+	// DeliveryInstructions are sent to the driver with delivery orders.
+	DeliveryInstructions string `json:"DeliveryInstructions"`
+
 	// This is a dominos specific field, and should one of the following...
 	// "House", "Apartment", "Business", "Campus/Base", "Hotel", or "Other"
 	AddrType string `json:"Type"`
@@ -92,7 +423,7 @@ func StreetAddrFromAddress(addr Address) *StreetAddr {
 		return res
 	}
 
-	return &StreetAddr{
+	res := &StreetAddr{
 		Street:     addr.LineOne(),
 		StreetNum:  streetNum,
 		CityName:   addr.City(),
@@ -100,14 +431,29 @@ func StreetAddrFromAddress(addr Address) *StreetAddr {
 		Zipcode:    addr.Zip(),
 		StreetName: streetName,
 	}
+	if u, ok := addr.(UnitAddress); ok {
+		res.UnitType, res.UnitNumber = u.Unit()
+		res.DeliveryInstructions = u.Instructions()
+	}
+	return res
 }
 
-// Equal will test if an s is the same as the Address given.
+// Equal will test if an s is the same as the Address given. If the Address
+// is also a UnitAddress then the unit and delivery instructions are compared
+// as well.
 func (s *StreetAddr) Equal(a Address) bool {
-	return s.City() == a.City() &&
-		s.LineOne() == a.LineOne() &&
-		s.StateCode() == a.StateCode() &&
-		s.Zip() == a.Zip()
+	if s.City() != a.City() ||
+		s.LineOne() != a.LineOne() ||
+		s.StateCode() != a.StateCode() ||
+		s.Zip() != a.Zip() {
+		return false
+	}
+	if u, ok := a.(UnitAddress); ok {
+		typ, num := u.Unit()
+		return s.UnitType == typ && s.UnitNumber == num &&
+			s.DeliveryInstructions == u.Instructions()
+	}
+	return true
 }
 
 // LineOne gives the street in the following format
@@ -135,3 +481,13 @@ func (s *StreetAddr) StateCode() string {
 func (s *StreetAddr) City() string {
 	return s.CityName
 }
+
+// Unit returns the unit type and number of the address.
+func (s *StreetAddr) Unit() (typ, number string) {
+	return s.UnitType, s.UnitNumber
+}
+
+// Instructions returns the address' delivery instructions.
+func (s *StreetAddr) Instructions() string {
+	return s.DeliveryInstructions
+}
@@ -16,16 +16,25 @@ type Address struct {
 	CityName string `config:"cityname" json:"cityname"`
 	State    string `config:"state" json:"state"`
 	Zipcode  string `config:"zipcode" json:"zipcode"`
+
+	UnitType             string `config:"unittype" json:"unittype"`
+	UnitNumber           string `config:"unitnumber" json:"unitnumber"`
+	DeliveryInstructions string `config:"instructions" json:"instructions"`
 }
 
 // FromAddress makes an obj.Address from an address interface.
 func FromAddress(a dawg.Address) *Address {
-	return &Address{
+	addr := &Address{
 		Street:   a.LineOne(),
 		CityName: a.City(),
 		State:    a.StateCode(),
 		Zipcode:  a.Zip(),
 	}
+	if u, ok := a.(dawg.UnitAddress); ok {
+		addr.UnitType, addr.UnitNumber = u.Unit()
+		addr.DeliveryInstructions = u.Instructions()
+	}
+	return addr
 }
 
 // LineOne returns the first line of the address
@@ -48,18 +57,28 @@ func (a *Address) City() string {
 	return a.CityName
 }
 
-// Zip returns the zip code.
+// Zip returns the zip code, or postal code outside of the US.
 func (a *Address) Zip() string {
-	if strings.Contains(a.Zipcode, " ") {
-		panic(fmt.Sprintf("bad zipcode %s", a.Zipcode))
-	}
-	if len(a.Zipcode) == 5 {
-		return a.Zipcode
+	if zip, ok := dawg.ParsePostalCode(a.Zipcode); ok {
+		return zip
 	}
 	return ""
 }
 
-var _ dawg.Address = (*Address)(nil)
+// Unit returns the unit type and number.
+func (a *Address) Unit() (typ, number string) {
+	return a.UnitType, a.UnitNumber
+}
+
+// Instructions returns the instructions for the delivery driver.
+func (a *Address) Instructions() string {
+	return a.DeliveryInstructions
+}
+
+var (
+	_ dawg.Address     = (*Address)(nil)
+	_ dawg.UnitAddress = (*Address)(nil)
+)
 
 // AddressFmt returns a formatted address string from and Address interface.
 func AddressFmt(a dawg.Address) string {
@@ -77,7 +96,7 @@ func AddressFmtIndent(a dawg.Address, l int) string {
 	}
 
 	return fmt.Sprintf(format,
-		a.LineOne(),
+		lineOne(a),
 		strings.Repeat(" ", l),
 		a.City(),
 		a.StateCode(),
@@ -85,6 +104,22 @@ func AddressFmtIndent(a dawg.Address, l int) string {
 	)
 }
 
+// lineOne returns the first line of an address with its unit.
+func lineOne(a dawg.Address) string {
+	u, ok := a.(dawg.UnitAddress)
+	if !ok {
+		return a.LineOne()
+	}
+	typ, num := u.Unit()
+	if num == "" {
+		return a.LineOne()
+	}
+	if typ == "" || typ == "#" {
+		return fmt.Sprintf("%s #%s", a.LineOne(), num)
+	}
+	return fmt.Sprintf("%s %s %s", a.LineOne(), typ, num)
+}
+
 func (a Address) String() string {
 	return AddressFmt(&a)
 }
@@ -52,6 +52,9 @@ func AllCommands(builder cli.Builder) []*cobra.Command {
 		commands.NewConfigCmd(builder).Cmd(),
 		NewMenuCmd(builder).Cmd(),
 		commands.NewOrderCmd(builder).Cmd(),
+		commands.NewTrackCmd(builder).Cmd(),
+		commands.NewReorderCmd(builder).Cmd(),
+		commands.NewAccountCmd(builder).Cmd(),
 		commands.NewAddAddressCmd(builder, os.Stdin).Cmd(),
 		commands.NewCompletionCmd(builder),
 	}
@@ -47,7 +47,9 @@ func NewApp(out io.Writer) *App {
 		opts:  opts.ApizzaFlags{},
 	}
 	app.CliCommand = cli.NewCommand("apizza", "Dominos pizza from the command line.", app.Run)
-	app.StoreFinder = client.NewStoreGetterFunc(app.getService, app.Address)
+	app.StoreFinder = client.NewPreferredStoreGetter(app.getService, func() string {
+		return app.gOpts.Prefer
+	}, app.Address)
 	cmd := app.Cmd()
 	cmd.PersistentPreRunE = app.prerun
 	cmd.PostRunE = app.postrun
@@ -185,6 +187,7 @@ func (a *App) initflags() {
 
 	a.gOpts.Install(persistflags)
 	a.opts.Install(flags)
+	persistflags.StringVar(&a.gOpts.Prefer, "prefer", "", "how to choose a store: 'closest', 'fastest', or 'best'")
 
 	persistflags.BoolVar(&test, "test", false, "testing flag (for development)")
 	persistflags.MarkHidden("test")
@@ -195,6 +198,13 @@ func (a *App) prerun(*cobra.Command, []string) (err error) {
 		err = a.DB().Delete("menu")
 	}
 	var e error
+	// the market has to be set before any addresses are parsed
+	market, e := dawg.LookupMarket(a.conf.Market)
+	if e != nil {
+		return e
+	}
+	dawg.SetMarket(market)
+
 	if a.gOpts.Address != "" {
 		// First look in the database as if the flag was a named address.
 		// Else check if the flag is a parsable address.
@@ -221,6 +231,10 @@ func (a *App) prerun(*cobra.Command, []string) (err error) {
 		a.conf.Service = a.gOpts.Service
 	}
 
+	if _, err := client.ParsePreference(a.gOpts.Prefer); err != nil {
+		return err
+	}
+
 	if a.gOpts.LogFile != "" {
 		dir := fp.Join(config.Folder(), "logs")
 		if _, err := os.Stat(dir); os.IsNotExist(err) {
@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 )
 
@@ -22,18 +25,123 @@ type Token struct {
 	// ExpiresIn is the time in seconds that it takes for the token to
 	// expire.
 	ExpiresIn int `json:"expires_in"`
+	// Expires is the time when the token expires. It is not sent by the
+	// oauth endpoint, see SetExpiration.
+	Expires time.Time `json:"expires,omitempty"`
 
 	transport http.RoundTripper
+	refresh   Refresher
+	mu        sync.Mutex
+}
+
+// Refresher is a function that uses a refresh token to get a new token.
+type Refresher func(ctx context.Context, refreshToken string) (*Token, error)
+
+// ErrNoRefresh is returned when a token is expired and cannot be refreshed.
+var ErrNoRefresh = errors.New("token is expired and has no way to be refreshed")
+
+// expiryDelta is how long before the actual expiration that the token is
+// considered expired so that requests don't race the expiration time.
+const expiryDelta = 30 * time.Second
+
+// SetExpiration sets the Expires field from the ExpiresIn field relative
+// to the current time. Should be called right after the token is received.
+func (t *Token) SetExpiration() {
+	if t.ExpiresIn > 0 {
+		t.Expires = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
+}
+
+// SetRefresher sets the function used to refresh the token once it expires.
+func (t *Token) SetRefresher(r Refresher) {
+	t.mu.Lock()
+	t.refresh = r
+	t.mu.Unlock()
+}
+
+// Expired returns true if the token has expired. A token without an
+// expiration time will never expire.
+func (t *Token) Expired() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.expiredLocked()
+}
+
+func (t *Token) expiredLocked() bool {
+	if t.Expires.IsZero() {
+		return false
+	}
+	return time.Now().Add(expiryDelta).After(t.Expires)
+}
+
+// Refresh will get a new access token using the refresh token.
+func (t *Token) Refresh(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.refreshLocked(ctx)
+}
+
+func (t *Token) refreshLocked(ctx context.Context) error {
+	if t.refresh == nil || t.RefreshToken == "" {
+		return ErrNoRefresh
+	}
+	tok, err := t.refresh(ctx, t.RefreshToken)
+	if err != nil {
+		return err
+	}
+	t.AccessToken = tok.AccessToken
+	t.Type = tok.Type
+	t.ExpiresIn = tok.ExpiresIn
+	if tok.RefreshToken != "" {
+		t.RefreshToken = tok.RefreshToken
+	}
+	t.Expires = tok.Expires
+	if t.Expires.IsZero() {
+		t.SetExpiration()
+	}
+	return nil
+}
+
+// Credentials is a copy of the parts of a token needed to use it again.
+type Credentials struct {
+	AccessToken  string
+	RefreshToken string
+	Type         string
+	Expires      time.Time
+}
+
+// Credentials returns a copy of the token's credentials, it is safe to
+// call while the token is being used or refreshed.
+func (t *Token) Credentials() Credentials {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Credentials{
+		AccessToken:  t.AccessToken,
+		RefreshToken: t.RefreshToken,
+		Type:         t.Type,
+		Expires:      t.Expires,
+	}
 }
 
 func (t *Token) authorization() string {
 	return fmt.Sprintf("%s %s", t.Type, t.AccessToken)
 }
 
-// RoundTrip implements the http.RoundTripper interface.
+// RoundTrip implements the http.RoundTripper interface. If the token has
+// expired it will be refreshed before the request is sent.
 func (t *Token) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.Lock()
+	if t.expiredLocked() {
+		if err := t.refreshLocked(req.Context()); err != nil {
+			t.mu.Unlock()
+			return nil, err
+		}
+	}
 	req.Header.Set("Authorization", t.authorization())
-	SetDawgUserAgent(req.Header)
+	t.mu.Unlock()
+	if req.Header.Get("User-Agent") == "" {
+		SetDawgUserAgent(req.Header)
+	}
 	return t.transport.RoundTrip(req)
 }
 
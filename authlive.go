@@ -2,6 +2,7 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -35,24 +36,34 @@ var (
 		Host:   "authproxy.dominos.com",
 		Path:   "/auth-proxy-service/login",
 	}
-
-	loginURL = &url.URL{
-		Scheme: "https",
-		Host:   orderHost,
-		Path:   "/power/login",
-	}
 )
 
-func authorize(c *http.Client, username, password string) error {
-	tok, err := gettoken(username, password)
+func authorize(ctx context.Context, c *client, username, password string) error {
+	tok, err := gettoken(ctx, c, username, password)
 	if err != nil {
 		return err
 	}
+	setToken(c, tok)
+	return nil
+}
+
+// setToken sets the token as the client's transport and gives it a way to
+// refresh itself using the client's original transport.
+func setToken(c *client, tok *auth.Token) {
+	tok.SetRefresher(refresher(c.clone()))
 	if c.Transport != nil {
 		tok.SetTransport(c.Transport)
 	}
 	c.Transport = tok
-	return nil
+}
+
+// token will return the client's auth token or nil if it has none.
+func (c *client) token() *auth.Token {
+	if c.Client == nil {
+		return nil
+	}
+	tok, _ := c.Transport.(*auth.Token)
+	return tok
 }
 
 var noRedirects = func(r *http.Request, via []*http.Request) error {
@@ -77,7 +88,7 @@ var scopes = []string{
 	"easyOrder:read",
 }
 
-func gettoken(username, password string) (*auth.Token, error) {
+func gettoken(ctx context.Context, c *client, username, password string) (*auth.Token, error) {
 	data := url.Values{
 		"grant_type":   {"password"},
 		"client_id":    {"nolo-rm"}, // nolo-rm if you want a refresh token, or just nolo for temporary token
@@ -86,8 +97,28 @@ func gettoken(username, password string) (*auth.Token, error) {
 		"username":     {username},
 		"password":     {password},
 	}
-	req := newPostReq(oauthURL, data)
-	resp, err := orderClient.Do(req)
+	return requestToken(ctx, c, data)
+}
+
+// refresher returns a function that refreshes tokens with the oauth
+// endpoint. The client should not be using the token being refreshed.
+func refresher(c *client) auth.Refresher {
+	return func(ctx context.Context, refreshToken string) (*auth.Token, error) {
+		return requestToken(ctx, c, url.Values{
+			"grant_type":    {"refresh_token"},
+			"client_id":     {"nolo-rm"},
+			"refresh_token": {refreshToken},
+		})
+	}
+}
+
+func requestToken(ctx context.Context, c *client, data url.Values) (*auth.Token, error) {
+	u := oauthURL
+	if c.authURL != nil {
+		u = c.authURL
+	}
+	req := c.newPostReq(ctx, u, data)
+	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -104,15 +135,16 @@ func gettoken(username, password string) (*auth.Token, error) {
 	if result.Error != nil {
 		return nil, result.Error
 	}
+	result.Token.SetExpiration()
 	return result.Token, nil
 }
 
-func login(c *client) (*UserProfile, error) {
+func login(ctx context.Context, c *client) (*UserProfile, error) {
 	data := url.Values{
 		"loyaltyIsActive": {"true"},
 		"rememberMe":      {"true"},
 	}
-	req := newPostReq(loginURL, data)
+	req := c.newPostReq(ctx, c.url("/power/login", nil), data)
 	res, err := c.Do(req)
 	if err != nil {
 		return nil, err
@@ -130,8 +162,8 @@ func login(c *client) (*UserProfile, error) {
 	return profile, json.Unmarshal(b, profile)
 }
 
-func newPostReq(u *url.URL, vals url.Values) *http.Request {
-	return &http.Request{
+func (c *client) newPostReq(ctx context.Context, u *url.URL, vals url.Values) *http.Request {
+	req := &http.Request{
 		Method:     "POST",
 		Proto:      "HTTP/1.1",
 		ProtoMajor: 1,
@@ -143,11 +175,97 @@ func newPostReq(u *url.URL, vals url.Values) *http.Request {
 		URL:  u,
 		Body: ioutil.NopCloser(strings.NewReader(vals.Encode())),
 	}
+	c.setUserAgent(req.Header)
+	return req.WithContext(ctx)
 }
 
 type client struct {
 	*http.Client
 	host string
+
+	// scheme and basePath make up the rest of the base url, an empty
+	// scheme defaults to "https".
+	scheme   string
+	basePath string
+
+	// lang is the language code sent to dominos, the market's default
+	// language is used if it is empty.
+	lang       string
+	market     *Market
+	userAgent  string
+	authURL    *url.URL
+	trackerURL *url.URL
+}
+
+// clone will copy the client so that changes to the http.Client (like
+// setting an auth token as the transport) are not shared.
+func (c *client) clone() *client {
+	cp := *c
+	if c.Client != nil {
+		hc := *c.Client
+		cp.Client = &hc
+	}
+	return &cp
+}
+
+func (c *client) language() string {
+	if c.lang == "" {
+		return c.getMarket().DefaultLanguage()
+	}
+	return c.lang
+}
+
+func (c *client) getMarket() *Market {
+	if c.market == nil {
+		return MarketUS
+	}
+	return c.market
+}
+
+func (c *client) setUserAgent(head http.Header) {
+	if c.userAgent != "" {
+		head.Set("User-Agent", c.userAgent)
+		return
+	}
+	auth.SetDawgUserAgent(head)
+}
+
+func (c *client) url(path string, params URLParam) *url.URL {
+	if params == nil {
+		params = &Params{}
+	}
+	scheme := c.scheme
+	if scheme == "" {
+		scheme = "https"
+	}
+	return &url.URL{
+		Scheme:   scheme,
+		Host:     c.host,
+		Path:     c.basePath + path,
+		RawQuery: params.Encode(),
+	}
+}
+
+func (c *client) newRequest(
+	ctx context.Context,
+	method, path string,
+	params URLParam,
+	body io.Reader,
+) *http.Request {
+	rc, ok := body.(io.ReadCloser)
+	if !ok && body != nil {
+		rc = ioutil.NopCloser(body)
+	}
+	req := &http.Request{
+		Method: method,
+		Host:   c.host,
+		Proto:  "HTTP/1.1",
+		Header: make(http.Header),
+		Body:   rc,
+		URL:    c.url(path, params),
+	}
+	c.setUserAgent(req.Header)
+	return req.WithContext(ctx)
 }
 
 func (c *client) do(req *http.Request) ([]byte, error) {
@@ -185,63 +303,12 @@ func dojson(d doer, v interface{}, r *http.Request) (err error) {
 	return json.NewDecoder(resp.Body).Decode(v)
 }
 
-func (c *client) get(path string, params URLParam) ([]byte, error) {
-	if params == nil {
-		params = &Params{}
-	}
-	return c.do(&http.Request{
-		Method: "GET",
-		Host:   c.host,
-		Proto:  "HTTP/1.1",
-		Header: make(http.Header),
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     c.host,
-			Path:     path,
-			RawQuery: params.Encode(),
-		},
-	})
-}
-
-func get(d doer, host, path string, params URLParam) (*http.Response, error) {
-	if params == nil {
-		params = &Params{}
-	}
-	return d.Do(&http.Request{
-		Method: "GET",
-		Host:   host,
-		Proto:  "HTTP/1.1",
-		Header: make(http.Header),
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     host,
-			Path:     path,
-			RawQuery: params.Encode(),
-		},
-	})
+func (c *client) get(ctx context.Context, path string, params URLParam) ([]byte, error) {
+	return c.do(c.newRequest(ctx, "GET", path, params, nil))
 }
 
-func (c *client) post(path string, params URLParam, r io.Reader) ([]byte, error) {
-	if params == nil {
-		params = &Params{}
-	}
-	rc, ok := r.(io.ReadCloser)
-	if !ok && r != nil {
-		rc = ioutil.NopCloser(r)
-	}
-	return c.do(&http.Request{
-		Method: "POST",
-		Host:   c.host,
-		Proto:  "HTTP/1.1",
-		Header: make(http.Header),
-		Body:   rc,
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     c.host,
-			Path:     path,
-			RawQuery: params.Encode(),
-		},
-	})
+func (c *client) post(ctx context.Context, path string, params URLParam, r io.Reader) ([]byte, error) {
+	return c.do(c.newRequest(ctx, "POST", path, params, r))
 }
 
 func unmarshalToken(r io.ReadCloser, t *auth.Token) error {
@@ -0,0 +1,89 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSignInWithToken_Refresh(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	var refreshes int
+	mux.HandleFunc("/oauth", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+			return
+		}
+		if r.Form.Get("grant_type") != "refresh_token" {
+			t.Errorf("expected a refresh, got grant_type %q", r.Form.Get("grant_type"))
+		}
+		if r.Form.Get("refresh_token") != "refresh-1" {
+			t.Errorf("wrong refresh token %q", r.Form.Get("refresh_token"))
+		}
+		if r.Header.Get("Authorization") != "" {
+			t.Error("the refresh request should not use the expired token")
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"access_token":"access-2","refresh_token":"refresh-2","token_type":"Bearer","expires_in":3600}`)
+	})
+	mux.HandleFunc("/power/login", func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer access-2" {
+			t.Errorf("login used the wrong token: %q", auth)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"CustomerID":"123","FirstName":"Test","Status":0}`)
+	})
+
+	c, err := NewClient(WithBaseURL(srv.URL), WithAuthURL(srv.URL+"/oauth"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := c.SignInWithToken(&Session{
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		Type:         "Bearer",
+		Expires:      time.Now().Add(-time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 1 {
+		t.Errorf("expected one token refresh, got %d", refreshes)
+	}
+	if user.ID != "123" {
+		t.Errorf("wrong customer id %q", user.ID)
+	}
+
+	sess, err := user.Session()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sess.AccessToken != "access-2" || sess.RefreshToken != "refresh-2" {
+		t.Errorf("session should have the refreshed token: %+v", sess)
+	}
+	if sess.Expired() {
+		t.Error("refreshed session should not be expired")
+	}
+
+	raw, err := json.Marshal(sess)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored := &Session{}
+	if err = json.Unmarshal(raw, stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.AccessToken != sess.AccessToken || !stored.Expires.Equal(sess.Expires) {
+		t.Error("session did not survive a round trip through json")
+	}
+	if _, err = c.SignInWithToken(&Session{}); err == nil {
+		t.Error("expected an error for an empty session")
+	}
+}
@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"os"
@@ -19,12 +20,12 @@ func TestBadCreds(t *testing.T) {
 	defer swapclient(8)()
 	tests.InitHelpers(t)
 
-	err := authorize(orderClient.Client, "5uup;hrg];ht8bijer$u9tot", "hurieahgr9[0249eingurivja")
+	err := authorize(context.Background(), orderClient, "5uup;hrg];ht8bijer$u9tot", "hurieahgr9[0249eingurivja")
 	tests.Exp(err)
 	if _, ok := orderClient.Transport.(*auth.Token); ok {
 		t.Error("bad authorization should not set the client transport to a token")
 	}
-	tok, err := gettoken("no", "and no")
+	tok, err := gettoken(context.Background(), orderClient, "no", "and no")
 	tests.Exp(err)
 	if tok != nil {
 		t.Errorf("expected nil %T", tok)
@@ -32,7 +33,7 @@ func TestBadCreds(t *testing.T) {
 	if _, ok := err.(*auth.Error); !ok {
 		t.Errorf("expected an *auth.Error got %T:\n%v", err, err)
 	}
-	user, err := login(orderClient)
+	user, err := login(context.Background(), orderClient)
 	tests.Exp(err)
 	if user != nil {
 		t.Errorf("expected nil %T", user)
@@ -45,7 +46,7 @@ func TestBadCreds(t *testing.T) {
 	orderClient.Client.Transport = newRoundTripper(func(*http.Request) error {
 		return errors.New("this should make the client fail")
 	})
-	tok, err = gettoken(username, password)
+	tok, err = gettoken(context.Background(), orderClient, username, password)
 	tests.Exp(err)
 	if tok != nil {
 		t.Errorf("expected nil %T", tok)
@@ -115,7 +116,7 @@ func TestToken(t *testing.T) {
 	addUserHandlers(t, mux)
 	tests.InitHelpers(t)
 
-	tok, err := gettoken(username, password)
+	tok, err := gettoken(context.Background(), orderClient, username, password)
 	tests.Check(err)
 	if tok == nil {
 		t.Fatalf("got nil %T got %v", tok, tok)
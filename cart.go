@@ -24,12 +24,14 @@ import (
 
 // New will create a new cart
 func New(b cartBuilder) *Cart {
-	storefinder := client.NewStoreGetterFunc(func() string {
+	storefinder := client.NewPreferredStoreGetter(func() string {
 		opts := b.GlobalOptions()
 		if opts.Service != "" {
 			return opts.Service
 		}
 		return b.Config().Service
+	}, func() string {
+		return b.GlobalOptions().Prefer
 	}, b.Address)
 
 	return &Cart{
@@ -170,9 +172,27 @@ ValidOrder:
 // PrintCurrentOrder will print out the current order.
 func (c *Cart) PrintCurrentOrder(full, color, price bool) error {
 	out.SetOutput(c.out)
+	if full && c.CurrentOrder != nil {
+		// the menu is only used for topping names so the order can still be
+		// printed without it
+		if err := c.db.UpdateTS("menu", c); err == nil {
+			for _, p := range c.CurrentOrder.Products {
+				p.SetMenu(c.Menu())
+			}
+		}
+	}
 	return out.PrintOrder(c.CurrentOrder, full, color, price)
 }
 
+// PrintReceipt will print the itemized price of the current order.
+func (c *Cart) PrintReceipt(color bool) error {
+	if c.CurrentOrder == nil {
+		return ErrNoCurrentOrder
+	}
+	out.SetOutput(c.out)
+	return out.PrintReceipt(c.CurrentOrder, color)
+}
+
 // UpdateAddressAndOrderID will update the current order's address and then update
 // the current order's StoreID by finding the nearest store for that address.
 func (c *Cart) UpdateAddressAndOrderID(currentAddr dawg.Address) error {
@@ -203,7 +223,14 @@ func (c *Cart) AddToppings(product string, toppings []string) error {
 	if c.CurrentOrder == nil {
 		return ErrNoCurrentOrder
 	}
-	return addToppingsToOrder(c.CurrentOrder, product, toppings)
+	if err := c.db.UpdateTS("menu", c); err != nil {
+		return err
+	}
+	menu := c.Menu()
+	if err := checkSides(menu, product, toppings); err != nil {
+		return err
+	}
+	return addToppingsToOrder(c.CurrentOrder, menu, product, toppings)
 }
 
 // AddProducts adds a list of products to the current order
@@ -217,12 +244,27 @@ func (c *Cart) AddProducts(products []string) error {
 	return addProducts(c.CurrentOrder, c.Menu(), products)
 }
 
+// AddCoupons adds a list of coupons to the current order. Coupons that are
+// not on the store's menu are still added so that dominos can check them
+// when the order is validated.
+func (c *Cart) AddCoupons(coupons []string) error {
+	if c.CurrentOrder == nil {
+		return ErrNoCurrentOrder
+	}
+	if err := c.db.UpdateTS("menu", c); err != nil {
+		return err
+	}
+	return addCoupons(c.CurrentOrder, c.Menu(), coupons)
+}
+
 // PrintOrders will print out all the orders saved in the database
 func (c *Cart) PrintOrders(verbose bool, color string) error {
 	return data.PrintOrders(c.db, c.out, verbose, color)
 }
 
-func addToppingsToOrder(o *dawg.Order, product string, toppings []string) (err error) {
+// addToppingsToOrder adds toppings and sides to a product in the order. The
+// toppings are checked against the menu unless it is nil.
+func addToppingsToOrder(o *dawg.Order, menu *dawg.Menu, product string, toppings []string) (err error) {
 	if product == "" {
 		return errors.New("what product are these toppings being added to")
 	}
@@ -232,7 +274,15 @@ func addToppingsToOrder(o *dawg.Order, product string, toppings []string) (err e
 			return fmt.Errorf("cannot find '%s' in the '%s' order", product, o.Name())
 		}
 
-		err = internal.AddTopping(top, p)
+		if internal.IsSide(top) {
+			si, ok := p.(dawg.SideItem)
+			if !ok {
+				return fmt.Errorf("cannot add sides to '%s'", product)
+			}
+			err = internal.AddSide(top, si)
+		} else {
+			err = internal.AddTopping(top, p, menu)
+		}
 		if err != nil {
 			return err
 		}
@@ -240,10 +290,30 @@ func addToppingsToOrder(o *dawg.Order, product string, toppings []string) (err e
 	return nil
 }
 
+// checkSides makes sure that all the sides in a list of toppings can be
+// added to a product on the menu.
+func checkSides(menu *dawg.Menu, product string, toppings []string) error {
+	v, err := menu.GetVariant(product)
+	if err != nil {
+		// not a menu item, dominos will have to check the sides
+		return nil
+	}
+	for _, top := range toppings {
+		if !internal.IsSide(top) {
+			continue
+		}
+		code := strings.Split(top[len(internal.SidePrefix):], ":")[0]
+		if !v.SideAvailable(code) {
+			return fmt.Errorf("%s is not an available side for %s", code, product)
+		}
+	}
+	return nil
+}
+
 func addProducts(o *dawg.Order, menu *dawg.Menu, products []string) (err error) {
 	var itm dawg.Item
 	for _, newP := range products {
-		itm, err = menu.GetVariant(newP)
+		itm, err = internal.FindProduct(menu, newP)
 		if err != nil {
 			return err
 		}
@@ -255,6 +325,21 @@ func addProducts(o *dawg.Order, menu *dawg.Menu, products []string) (err error)
 	return nil
 }
 
+func addCoupons(o *dawg.Order, menu *dawg.Menu, coupons []string) (err error) {
+	for _, code := range coupons {
+		coupon, e := menu.GetCoupon(code)
+		if e != nil {
+			err = o.AddCouponCode(code)
+		} else {
+			err = o.AddCoupon(coupon)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getOrderItem(order *dawg.Order, code string) dawg.Item {
 	for _, itm := range order.Products {
 		if itm.ItemCode() == code {
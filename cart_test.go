@@ -28,7 +28,7 @@ func TestToppings(t *testing.T) {
 	r, cart, order := setup(t)
 	defer r.CleanUp()
 
-	tests.Exp(internal.AddTopping("", testProduct))
+	tests.Exp(internal.AddTopping("", testProduct, nil))
 	order.Products = []*dawg.OrderProduct{testProduct}
 	tests.Fatal(data.SaveOrder(order, cart.out, r.DataBase))
 	tests.Fatal(cart.SetCurrentOrder(cmdtest.OrderName))
@@ -183,9 +183,81 @@ func TestHelpers_Err(t *testing.T) {
 	}
 	tests.Exp(addProducts(o, m, []string{"nope", "not a thing"}))
 	tests.Check(addProducts(o, m, []string{"12SCREEN"}))
-	tests.Exp(addToppingsToOrder(o, "nothere", []string{"K", "B"}))
-	tests.Exp(addToppingsToOrder(o, "", []string{"K", "B"}))
-	tests.Exp(addToppingsToOrder(o, "12SCREEN", []string{""}))
+	tests.Exp(addToppingsToOrder(o, nil, "nothere", []string{"K", "B"}))
+	tests.Exp(addToppingsToOrder(o, nil, "", []string{"K", "B"}))
+	tests.Exp(addToppingsToOrder(o, nil, "12SCREEN", []string{""}))
+}
+
+func TestAddCoupons(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{Coupons: map[string]*dawg.Coupon{
+		"9193": {Code: "9193", Tags: dawg.CouponTags{ValidServiceMethods: []string{dawg.Delivery}}},
+		"9174": {Code: "9174", Tags: dawg.CouponTags{ValidServiceMethods: []string{dawg.Carryout}}},
+	}}
+	o := &dawg.Order{ServiceMethod: dawg.Delivery}
+	tests.Check(addCoupons(o, menu, []string{"9193", "PROMO"}))
+	tests.Exp(addCoupons(o, menu, []string{"9174"}), "should not add a carryout coupon to a delivery order")
+	tests.Exp(addCoupons(o, menu, []string{"9193"}), "should not add the same coupon twice")
+	if len(o.Coupons) != 2 {
+		t.Fatalf("expected 2 coupons, got %d", len(o.Coupons))
+	}
+	if o.Coupons[1].Code != "PROMO" {
+		t.Error("coupons that are not on the menu should still be added")
+	}
+}
+
+func TestAddSides(t *testing.T) {
+	tests.InitHelpers(t)
+	o := &dawg.Order{Products: []*dawg.OrderProduct{{
+		ItemCommon: dawg.ItemCommon{Code: "W08PBNLW"},
+		Opts:       map[string]interface{}{},
+		Qty:        1,
+	}}}
+	tests.Check(addToppingsToOrder(o, nil, "W08PBNLW", []string{"side:SIDRAN:2", "side:HOTCUP"}))
+	sides := o.Products[0].Sides()
+	if sides["SIDRAN"] != 2 || sides["HOTCUP"] != 1 {
+		t.Errorf("wrong sides: %v", sides)
+	}
+	tests.Exp(addToppingsToOrder(o, nil, "W08PBNLW", []string{"side:"}))
+	tests.Exp(addToppingsToOrder(o, nil, "W08PBNLW", []string{"side:SIDRAN:x"}))
+	tests.Exp(addToppingsToOrder(o, nil, "W08PBNLW", []string{"side:SIDRAN:1:2"}))
+
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_BONELESS": {AvailableSides: "HOTCUP,SIDRAN"}},
+		Variants: map[string]*dawg.Variant{"W08PBNLW": {ProductCode: "S_BONELESS"}},
+	}
+	tests.Check(checkSides(menu, "W08PBNLW", []string{"K", "side:SIDRAN"}))
+	tests.Exp(checkSides(menu, "W08PBNLW", []string{"side:SIDMAR"}))
+}
+
+func TestAddToppings_Menu(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_PIZZA": {
+			ItemCommon:        dawg.ItemCommon{Code: "S_PIZZA", Tags: map[string]interface{}{"PartCount": "2"}},
+			ProductType:       "Pizza",
+			AvailableToppings: "X=0:0.5:1:1.5,P,K",
+		}},
+		Variants: map[string]*dawg.Variant{"12SCREEN": {ProductCode: "S_PIZZA"}},
+		Toppings: map[string]map[string]dawg.Topping{"Pizza": {
+			"X": {ItemCommon: dawg.ItemCommon{Code: "X"}},
+			"P": {ItemCommon: dawg.ItemCommon{Code: "P"}},
+			"K": {ItemCommon: dawg.ItemCommon{Code: "K"}},
+			"H": {ItemCommon: dawg.ItemCommon{Code: "H"}},
+		}},
+	}
+	o := &dawg.Order{Products: []*dawg.OrderProduct{{
+		ItemCommon: dawg.ItemCommon{Code: "12SCREEN"},
+		Opts:       map[string]interface{}{},
+		Qty:        1,
+	}}}
+	tests.Check(addToppingsToOrder(o, menu, "12SCREEN", []string{"P", "K:left:1.5"}))
+	tests.Exp(addToppingsToOrder(o, menu, "12SCREEN", []string{"Pp"}), "should not add a topping that is not on the menu")
+	tests.Exp(addToppingsToOrder(o, menu, "12SCREEN", []string{"H"}), "should not add an unavailable topping")
+	tests.Exp(addToppingsToOrder(o, menu, "12SCREEN", []string{"X:full:2"}), "should not add more sauce than is allowed")
+	if _, ok := o.Products[0].Opts["H"]; ok {
+		t.Error("unavailable topping was added to the product")
+	}
 }
 
 func setup(t *testing.T) (*cmdtest.Recorder, *Cart, *dawg.Order) {
@@ -195,3 +267,35 @@ func setup(t *testing.T) (*cmdtest.Recorder, *Cart, *dawg.Order) {
 	cart.SetOutput(ioutil.Discard)
 	return r, cart, cmdtest.NewTestOrder()
 }
+
+func TestAddProducts_Sizes(t *testing.T) {
+	tests.InitHelpers(t)
+	menu := &dawg.Menu{
+		Products: map[string]*dawg.Product{"S_PIZZA": {
+			ItemCommon:  dawg.ItemCommon{Code: "S_PIZZA", Name: "Pizza"},
+			ProductType: "Pizza",
+			Variants:    []string{"12SCREEN", "12THIN", "14THIN"},
+		}},
+		Variants: map[string]*dawg.Variant{
+			"12SCREEN": {ItemCommon: dawg.ItemCommon{Code: "12SCREEN"}, ProductCode: "S_PIZZA", SizeCode: "12", FlavorCode: "HANDTOSS"},
+			"12THIN":   {ItemCommon: dawg.ItemCommon{Code: "12THIN"}, ProductCode: "S_PIZZA", SizeCode: "12", FlavorCode: "THIN"},
+			"14THIN":   {ItemCommon: dawg.ItemCommon{Code: "14THIN"}, ProductCode: "S_PIZZA", SizeCode: "14", FlavorCode: "THIN"},
+		},
+		Sizes: map[string]map[string]dawg.VariantOption{"Pizza": {
+			"12": {Code: "12", Name: `Medium (12")`, SortSeq: "04"},
+			"14": {Code: "14", Name: `Large (14")`, SortSeq: "05"},
+		}},
+		Flavors: map[string]map[string]dawg.VariantOption{"Pizza": {
+			"HANDTOSS": {Code: "HANDTOSS", Name: "Hand Tossed", SortSeq: "01"},
+			"THIN":     {Code: "THIN", Name: "Crunchy Thin Crust", SortSeq: "03"},
+		}},
+	}
+	o := &dawg.Order{}
+	tests.Check(addProducts(o, menu, []string{"pizza:medium:thin", "pizza:medium", "pizza:large"}))
+	for i, code := range []string{"12THIN", "12SCREEN", "14THIN"} {
+		tests.StrEq(o.Products[i].Code, code, "wrong variant for product %d", i)
+	}
+	tests.Exp(addProducts(o, menu, []string{"pizza:large:hand tossed"}), "large pizzas are only thin")
+	tests.Exp(addProducts(o, menu, []string{"pizza:huge"}))
+	tests.Exp(addProducts(o, menu, []string{"pizza:large:thin:extra"}))
+}
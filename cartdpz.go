@@ -2,11 +2,13 @@ package commands
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/harrybrwn/apizza/cmd/cart"
 	"github.com/harrybrwn/apizza/cmd/cli"
@@ -36,7 +38,10 @@ func NewCartCmd(b cli.Builder) cli.CliCommand {
 	cmd := c.Cmd()
 
 	cmd.Long = `The cart command gets information on and edit all of the user
-created orders.`
+created orders.
+
+Products can be added with --add by their code or by giving the product, size,
+and crust, for example --add "pizza:large:thin". The crust can be left out.`
 
 	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
@@ -47,12 +52,14 @@ created orders.`
 	cmd.ValidArgsFunction = c.cart.OrdersCompletion
 
 	c.Flags().BoolVar(&c.validate, "validate", c.validate, "Send an order to the dominos order-validation endpoint")
-	c.Flags().BoolVar(&c.price, "price", c.price, "Show to price of an order")
+	c.Flags().BoolVar(&c.price, "price", c.price, "Show an itemized receipt for the order")
 	c.Flags().BoolVarP(&c.delete, "delete", "d", c.delete, "Delete the order from the database")
 
-	c.Flags().StringSliceVarP(&c.add, "add", "a", c.add, "Add any number of products to a specific order")
+	c.Flags().StringSliceVarP(&c.add, "add", "a", c.add, "Add any number of products to a specific order by code or as '<product>:<size>:<crust>' (or toppings and 'side:<code>:<qty>' sides with --product)")
 	c.Flags().StringVarP(&c.remove, "remove", "r", c.remove, "Remove a product from the order")
 	c.Flags().StringVarP(&c.product, "product", "p", "", "Give the product that will be effected by --add or --remove")
+	c.Flags().StringSliceVar(&c.coupons, "coupon", c.coupons, "Add coupons to the order by coupon code")
+	c.Flags().StringVar(&c.removeCoupon, "remove-coupon", "", "Remove a coupon from the order")
 
 	c.Flags().BoolVarP(&c.verbose, "verbose", "v", c.verbose, "Print cart verbosely")
 
@@ -75,6 +82,9 @@ type cartCmd struct {
 	remove  string // yes, you can only remove one thing at a time
 	product string
 
+	coupons      []string
+	removeCoupon string
+
 	topping    bool // not actually a flag anymore
 	getaddress func() dawg.Address
 }
@@ -123,7 +133,19 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	if len(c.remove) > 0 {
-		if c.topping {
+		if c.topping && internal.IsSide(c.remove) {
+			code := strings.Split(c.remove[len(internal.SidePrefix):], ":")[0]
+			err = fmt.Errorf("cannot find '%s' in the '%s' order", c.product, order.Name())
+			for _, p := range order.Products {
+				if p.Code == c.product {
+					err = p.RemoveSide(code)
+					break
+				}
+			}
+			if err != nil {
+				return err
+			}
+		} else if c.topping {
 			for _, p := range order.Products {
 				if _, ok := p.Options()[c.remove]; ok || p.Code == c.product {
 					delete(p.Opts, c.remove)
@@ -138,6 +160,23 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		return c.cart.SaveAndReset()
 	}
 
+	if c.removeCoupon != "" {
+		if err = order.RemoveCoupon(c.removeCoupon); err != nil {
+			return err
+		}
+		if len(c.coupons) == 0 && len(c.add) == 0 {
+			return c.cart.SaveAndReset()
+		}
+	}
+	if len(c.coupons) > 0 {
+		if err = c.cart.AddCoupons(c.coupons); err != nil {
+			return err
+		}
+		if len(c.add) == 0 {
+			return c.cart.SaveAndReset()
+		}
+	}
+
 	if len(c.add) > 0 {
 		if c.topping {
 			err = c.cart.AddToppings(c.product, c.add)
@@ -150,7 +189,13 @@ func (c *cartCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		// save order and return early before order is printed out
 		return c.cart.SaveAndReset()
 	}
-	return c.cart.PrintCurrentOrder(true, c.color, c.price)
+	if c.price {
+		if err = c.cart.PrintCurrentOrder(true, c.color, false); err != nil {
+			return err
+		}
+		return c.cart.PrintReceipt(c.color)
+	}
+	return c.cart.PrintCurrentOrder(true, c.color, false)
 }
 
 func newAddOrderCmd(b cli.Builder) cli.CliCommand {
@@ -194,12 +239,16 @@ func (c *addOrderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	// - add a list of products in parallel with a list of toppings (vectorized approach)
 	// - add some weird extra syntax to do both (bad idea)
 	if c.product != "" {
-		prod, err := c.Store().GetVariant(c.product)
+		menu, err := c.Store().Menu()
+		if err != nil {
+			return err
+		}
+		prod, err := internal.FindProduct(menu, c.product)
 		if err != nil {
 			return err
 		}
 		for _, t := range c.toppings {
-			if err = internal.AddTopping(t, prod); err != nil {
+			if err = internal.AddTopping(t, prod, menu); err != nil {
 				return err
 			}
 		}
@@ -218,15 +267,33 @@ func NewOrderCmd(b cli.Builder) cli.CliCommand {
 		verbose:    false,
 		color:      Color,
 		getaddress: b.Address,
+		getprefer: func() string {
+			return b.GlobalOptions().Prefer
+		},
+		newStore:   dawg.NewStore,
+		placeOrder: (*dawg.Order).PlaceOrder,
+		newTracker: dawg.NewTracker,
 	}
 	c.CliCommand = b.Build("order", "Send an order from the cart to dominos.", c)
 	c.db = b.DB()
 	c.Cmd().Long = `The order command is the final destination for an order. This is where
 the order will be populated with payment information and sent off to dominos.
 
-The --cvv flag must be specified, and the config file will never store the
-cvv. In addition to keeping the cvv safe, payment information will never be
-stored the program cache with orders.
+The --pay flag sets how the order is paid for:
+  card            a credit card (the default)
+  saved:<name>    a card saved to the signed in account (see 'apizza account')
+  giftcard        a gift card, given with --number and --pin
+  cash            cash at the door or when picking up the order
+
+When paying with a card the --cvv flag must be specified, and the config file
+will never store the cvv. In addition to keeping the cvv safe, payment
+information will never be stored the program cache with orders.
+
+Orders will not be sent if the store is closed for the order's service method.
+
+The --at flag schedules the order for a later time instead of sending it right
+away, for example --at "2026-11-02 12:15". The time is in the store's time zone
+and must be when the store is open.
 `
 	c.Cmd().PreRunE = func(cmd *cobra.Command, args []string) error {
 		if len(args) > 1 {
@@ -243,11 +310,15 @@ stored the program cache with orders.
 	flags.StringVar(&c.fname, "first-name", "", "Set the first name that will be used for this order")
 	flags.StringVar(&c.fname, "last-name", "", "Set the last name that will be used for this order")
 
+	flags.StringVar(&c.pay, "pay", "card", "how to pay for the order (card, saved:<name>, giftcard, or cash)")
 	flags.IntVar(&c.cvv, "cvv", 0, "Set the card's cvv number for this order")
 	flags.StringVar(&c.number, "number", "", "the card number used for orderings")
 	flags.StringVar(&c.expiration, "expiration", "", "the card's expiration date")
+	flags.StringVar(&c.pin, "pin", "", "the gift card's pin")
+	flags.StringVar(&c.at, "at", "", "schedule the order for a later time (\"YYYY-MM-DD HH:MM\")")
 
 	flags.BoolVarP(&c.yes, "yes", "y", c.yes, "do not prompt the user with a question")
+	flags.BoolVarP(&c.track, "track", "t", c.track, "follow the order in the order tracker after it is sent")
 	flags.BoolVar(&c.logonly, "log-only", false, "")
 	flags.MarkHidden("log-only")
 	return c
@@ -263,14 +334,23 @@ type orderCmd struct {
 
 	email, phone string
 	fname, lname string
+	pay          string
 	cvv          int
 	number       string
 	expiration   string
+	pin          string
+	at           string
 	yes          bool
 	color        bool
 
 	logonly    bool
 	getaddress func() dawg.Address
+	getprefer  func() string
+
+	// these send the order to dominos and follow it in the tracker
+	newStore   func(id, service string, addr dawg.Address) (*dawg.Store, error)
+	placeOrder func(*dawg.Order) error
+	newTracker func(phone, orderID string) *dawg.Tracker
 }
 
 func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
@@ -284,28 +364,22 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		return errors.New("cannot handle multiple orders")
 	}
 
-	if c.cvv == 0 {
-		return errors.New("must have cvv number. (see --cvv)")
+	payment, err := c.paymentMethod()
+	if err != nil {
+		return err
 	}
 	order, err := data.GetOrder(args[0], c.db)
 	if err != nil {
 		return err
 	}
-
-	num := eitherOr(c.number, config.GetString("card.number"))
-	exp := eitherOr(c.expiration, config.GetString("card.expiration"))
-	if num == "" {
-		return errors.New("no card number given")
-	}
-	if exp == "" {
-		return errors.New("no card expiration date given")
-	}
-
-	card := dawg.NewCard(num, exp, c.cvv)
-	if err = dawg.ValidateCard(card); err != nil {
-		return err
+	if order.CustomerID != "" {
+		// orders with loyalty rewards have to be sent by the account that has the points
+		user, err := signIn(c.db)
+		if err != nil {
+			return err
+		}
+		order.SetCustomer(user)
 	}
-	order.AddCard(card)
 
 	names := strings.Split(config.GetString("name"), " ")
 	if len(names) >= 1 {
@@ -319,13 +393,17 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 
 	if !order.Address.Equal(c.getaddress()) {
 		order.Address = dawg.StreetAddrFromAddress(c.getaddress())
-		s, err := dawg.NearestStore(c.getaddress(), order.ServiceMethod)
+		s, err := client.FindStore(c.getaddress(), order.ServiceMethod, c.getprefer())
 		if err != nil {
 			return err
 		}
 		order.StoreID = s.ID
 	}
 
+	if err = order.AddPaymentMethod(payment); err != nil {
+		return err
+	}
+
 	c.Printf("Ordering dominos for %s to %s\n\n", order.ServiceMethod, strings.Replace(obj.AddressFmt(order.Address), "\n", " ", -1))
 
 	if c.logonly {
@@ -333,6 +411,23 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		return nil
 	}
 
+	store, err := c.newStore(order.StoreID, order.ServiceMethod, order.Address)
+	if err != nil {
+		return err
+	}
+	if err = store.AcceptsPayment(payment); err != nil {
+		return err
+	}
+	if c.at != "" {
+		if err = scheduleOrder(order, store, c.at); err != nil {
+			return err
+		}
+		at, _ := order.FutureTime(store)
+		c.Printf("The order is scheduled for %s\n", at.Format(promisedLayout))
+	} else if err = store.CheckOpen(order.ServiceMethod, time.Now()); err != nil {
+		return err
+	}
+
 	if !c.yes {
 		if !internal.YesOrNo(os.Stdin, "Would you like to purchase this order? (y/n)") {
 			return nil
@@ -340,13 +435,19 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	c.Printf("sending order '%s'...\n", order.Name())
-	err = order.PlaceOrder()
+	err = c.placeOrder(order)
 	// logging happens after so any data from placeorder is included
 	log.Println("sending order:", dawg.OrderToJSON(order))
+	placed := err == nil
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %s\n", err.Error())
+	} else if err = data.SaveLastOrder(order, c.db); err != nil {
+		log.Println("could not save placed order:", err)
 	}
 	c.Printf("sent to %s %s\n", order.Address.LineOne(), order.Address.City())
+	if at, ok := order.FutureTime(store); ok && placed {
+		c.Printf("promised for %s\n", at.Format(promisedLayout))
+	}
 
 	if c.verbose {
 		if order.ServiceMethod == dawg.Delivery {
@@ -357,9 +458,85 @@ func (c *orderCmd) Run(cmd *cobra.Command, args []string) (err error) {
 		}
 		c.Printf("%+v\n", order)
 	}
+	if c.track && placed {
+		return followOrder(context.Background(), c.newTracker(order.Phone, order.TrackingID()), c.Output())
+	}
 	return nil
 }
 
+// savedCardPrefix is used with --pay to choose a card saved to the account.
+const savedCardPrefix = "saved:"
+
+// paymentMethod gets the payment method chosen with the --pay flag.
+func (c *orderCmd) paymentMethod() (dawg.PaymentMethod, error) {
+	pay := strings.ToLower(c.pay)
+	switch {
+	case pay == "cash":
+		return dawg.Cash{}, nil
+	case pay == "giftcard":
+		if c.number == "" {
+			return nil, errors.New("no gift card number given (see --number)")
+		}
+		return &dawg.GiftCard{Number: c.number, Pin: c.pin}, nil
+	case strings.HasPrefix(pay, savedCardPrefix):
+		return c.savedCard(c.pay[len(savedCardPrefix):])
+	case pay == "card":
+		if c.cvv == 0 {
+			return nil, errors.New("must have cvv number. (see --cvv)")
+		}
+		num := eitherOr(c.number, config.GetString("card.number"))
+		exp := eitherOr(c.expiration, config.GetString("card.expiration"))
+		if num == "" {
+			return nil, errors.New("no card number given")
+		}
+		if exp == "" {
+			return nil, errors.New("no card expiration date given")
+		}
+		card := dawg.NewCard(num, exp, c.cvv)
+		if card == nil {
+			return nil, errors.New("bad card expiration date format")
+		}
+		if err := dawg.ValidateCard(card); err != nil {
+			return nil, err
+		}
+		return dawg.ToPayment(card), nil
+	}
+	return nil, fmt.Errorf("unknown payment method '%s' (should be card, saved:<name>, giftcard, or cash)", c.pay)
+}
+
+// savedCard finds a card saved to the signed in account by its nickname.
+func (c *orderCmd) savedCard(name string) (*dawg.UserCard, error) {
+	user, err := signIn(c.db)
+	if err != nil {
+		return nil, err
+	}
+	card, err := user.FindCard(name)
+	if err != nil {
+		return nil, err
+	}
+	if card.IsExpired {
+		return nil, fmt.Errorf("saved card '%s' has expired", card.NickName)
+	}
+	return card, nil
+}
+
+const (
+	// atLayout is the layout used by the --at flag.
+	atLayout = "2006-01-02 15:04"
+	// promisedLayout is used to show the time a scheduled order is promised.
+	promisedLayout = "Mon Jan 2 3:04pm"
+)
+
+// scheduleOrder sets an order's future time from a time given in the
+// store's time zone.
+func scheduleOrder(o *dawg.Order, s *dawg.Store, at string) error {
+	t, err := time.ParseInLocation(atLayout, strings.TrimSpace(at), s.Location())
+	if err != nil {
+		return fmt.Errorf("could not read the time '%s', use the format \"YYYY-MM-DD HH:MM\"", at)
+	}
+	return o.SetFutureTime(s, t)
+}
+
 func eitherOr(s1, s2 string) string {
 	if len(s1) == 0 {
 		return s2
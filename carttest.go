@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
@@ -304,6 +306,67 @@ func TestOrder_Err(t *testing.T) {
 	tests.Exp(cmd.Run(cmd.Cmd(), []string{"testorder"}))
 }
 
+func TestOrder_PaymentMethod(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	cmd := NewOrderCmd(r).(*orderCmd)
+
+	for _, tc := range []struct {
+		flags   []string
+		payType string
+	}{
+		{[]string{"--pay=cash"}, dawg.CashPayment},
+		{[]string{"--pay=GiftCard", "--number=6006491", "--pin=1234"}, dawg.GiftCardPayment},
+		{[]string{"--pay=card", "--cvv=123", "--number=4111111111111111", "--expiration=01/30"}, dawg.CreditCardPayment},
+	} {
+		cmd.pay, cmd.number, cmd.pin, cmd.cvv = "card", "", "", 0
+		tests.Check(cmd.Cmd().ParseFlags(tc.flags))
+		p, err := cmd.paymentMethod()
+		tests.Check(err)
+		if p != nil && p.PaymentType() != tc.payType {
+			t.Errorf("expected a %s payment, got %s", tc.payType, p.PaymentType())
+		}
+	}
+
+	cmd.pay, cmd.number = "giftcard", ""
+	_, err := cmd.paymentMethod()
+	tests.Exp(err, "expected an error for a gift card without a number")
+	cmd.pay = "bitcoin"
+	_, err = cmd.paymentMethod()
+	tests.Exp(err, "expected an error for an unknown payment method")
+	cmd.pay = "saved:work"
+	_, err = cmd.paymentMethod()
+	tests.Exp(err, "should not find saved cards without signing in")
+}
+
+func TestScheduleOrder(t *testing.T) {
+	store := &dawg.Store{TimeZoneMinutes: -5 * 60, ServiceHours: map[string]dawg.StoreHours{
+		dawg.Carryout: {Mon: []dawg.OpenHours{{OpenTime: "10:00", CloseTime: "22:00"}}},
+	}}
+	o := &dawg.Order{ServiceMethod: dawg.Carryout}
+	if err := scheduleOrder(o, store, "noon tomorrow"); err == nil {
+		t.Error("expected an error for a bad time")
+	}
+	if err := scheduleOrder(o, store, "2000-01-03 12:15"); err == nil {
+		t.Error("expected an error for a time in the past")
+	}
+	// the next monday at 12:15 in the store's time zone
+	next := time.Now().In(store.Location()).AddDate(0, 0, 2)
+	for next.Weekday() != time.Monday {
+		next = next.AddDate(0, 0, 1)
+	}
+	at := next.Format("2006-01-02") + " 12:15"
+	if err := scheduleOrder(o, store, at); err != nil {
+		t.Fatal(err)
+	}
+	if o.FutureOrderTime != at+":00" {
+		t.Errorf("wrong future order time: %s", o.FutureOrderTime)
+	}
+	if err := scheduleOrder(o, store, next.Format("2006-01-02")+" 23:00"); err == nil {
+		t.Error("expected an error when the store is closed")
+	}
+}
+
 func TestEitherOr(t *testing.T) {
 	if eitherOr("one", "") != "one" {
 		t.Error("wrong result from 'eitherOr'")
@@ -0,0 +1,267 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client is a dominos api client. It has all the same functionality as the
+// package level functions but it can be configured to send its requests to a
+// different host, through a custom http.Client, or in a different language.
+//
+// The package level functions all use a default client that sends requests
+// to order.dominos.com.
+//
+//	c, err := dawg.NewClient(dawg.WithBaseURL("http://localhost:8080"))
+//	if err != nil {
+//		// handle error
+//	}
+//	store, err := c.NearestStore(&address, dawg.Delivery)
+type Client struct {
+	cli *client
+}
+
+// ClientOption is a function that configures a Client.
+type ClientOption func(*client) error
+
+// NewClient will create a new Client. Without any options the Client will
+// behave exactly the same as the package level functions.
+func NewClient(opts ...ClientOption) (*Client, error) {
+	c := &client{
+		scheme: "https",
+		Client: newHTTPClient(),
+	}
+	for _, opt := range opts {
+		if err := opt(c); err != nil {
+			return nil, err
+		}
+	}
+	// the market only gives the defaults for anything
+	// that was not set by the other options
+	m := c.getMarket()
+	if c.host == "" {
+		c.host = m.Host
+	}
+	if c.lang == "" {
+		c.lang = m.DefaultLanguage()
+	}
+	return &Client{cli: c}, nil
+}
+
+// WithBaseURL sets the url that all of the client's requests will be sent
+// to. The url's path will be used as a prefix for all the api endpoints so
+// that the client can be used with a proxy.
+func WithBaseURL(base string) ClientOption {
+	return func(c *client) error {
+		u, err := url.Parse(base)
+		if err != nil {
+			return err
+		}
+		if u.Host == "" {
+			return fmt.Errorf("base url %q has no host", base)
+		}
+		c.host = u.Host
+		if u.Scheme != "" {
+			c.scheme = u.Scheme
+		}
+		c.basePath = strings.TrimSuffix(u.Path, "/")
+		return nil
+	}
+}
+
+// WithScheme sets the url scheme used by the client, should be "http" or "https".
+func WithScheme(scheme string) ClientOption {
+	return func(c *client) error {
+		if scheme != "http" && scheme != "https" {
+			return fmt.Errorf("unsupported url scheme %q", scheme)
+		}
+		c.scheme = scheme
+		return nil
+	}
+}
+
+// WithHTTPClient sets the http.Client that will be used to send requests.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *client) error {
+		if hc == nil {
+			return errors.New("cannot use a nil http.Client")
+		}
+		c.Client = hc
+		return nil
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request.
+func WithUserAgent(agent string) ClientOption {
+	return func(c *client) error {
+		c.userAgent = agent
+		return nil
+	}
+}
+
+// WithLanguage sets the language code used for menus and orders.
+func WithLanguage(lang string) ClientOption {
+	return func(c *client) error {
+		if lang == "" {
+			return errors.New("empty language code")
+		}
+		c.lang = lang
+		return nil
+	}
+}
+
+// WithMarket sets the market that the client is used in. The market's host
+// and default language are used unless WithBaseURL or WithLanguage are also
+// given, in any order.
+func WithMarket(m *Market) ClientOption {
+	return func(c *client) error {
+		if m == nil {
+			return errors.New("cannot use a nil market")
+		}
+		c.market = m
+		return nil
+	}
+}
+
+// WithAuthURL sets the oauth endpoint used when signing in.
+func WithAuthURL(raw string) ClientOption {
+	return func(c *client) error {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		c.authURL = u
+		return nil
+	}
+}
+
+// WithTrackerURL sets the endpoint used by the order tracker.
+func WithTrackerURL(raw string) ClientOption {
+	return func(c *client) error {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return err
+		}
+		c.trackerURL = u
+		return nil
+	}
+}
+
+// NearestStore gets the dominos location closest to the given address.
+// See the NearestStore function.
+func (c *Client) NearestStore(addr Address, service string) (*Store, error) {
+	return getNearestStore(context.Background(), c.cli, addr, service)
+}
+
+// NearestStoreContext is the same as NearestStore but with a context.
+func (c *Client) NearestStoreContext(ctx context.Context, addr Address, service string) (*Store, error) {
+	return getNearestStore(ctx, c.cli, addr, service)
+}
+
+// Market returns the market that the client is used in.
+func (c *Client) Market() *Market {
+	return c.cli.getMarket()
+}
+
+// ParseAddress parses an address in the client's market.
+// See the ParseAddress function.
+func (c *Client) ParseAddress(raw string) (*StreetAddr, error) {
+	return c.cli.getMarket().ParseAddress(raw)
+}
+
+// ValidateAddress checks an address with the client's store locator.
+// See the ValidateAddress function.
+func (c *Client) ValidateAddress(addr Address) (*StreetAddr, Granularity, error) {
+	return validateAddress(context.Background(), c.cli, addr)
+}
+
+// ValidateAddressContext is the same as ValidateAddress but with a context.
+func (c *Client) ValidateAddressContext(ctx context.Context, addr Address) (*StreetAddr, Granularity, error) {
+	return validateAddress(ctx, c.cli, addr)
+}
+
+// GetNearbyStores gets all the nearby stores fully initialized.
+// See the GetNearbyStores function.
+func (c *Client) GetNearbyStores(addr Address, service string) ([]*Store, error) {
+	return asyncNearbyStores(context.Background(), c.cli, addr, service)
+}
+
+// GetNearbyStoresContext is the same as GetNearbyStores but with a context.
+func (c *Client) GetNearbyStoresContext(ctx context.Context, addr Address, service string) ([]*Store, error) {
+	return asyncNearbyStores(ctx, c.cli, addr, service)
+}
+
+// FindNearbyStores initializes all the nearby stores and returns the errors
+// for each store that failed. See the FindNearbyStores function.
+func (c *Client) FindNearbyStores(addr Address, service string) (*NearbyStores, error) {
+	return nearbyStores(context.Background(), c.cli, addr, service)
+}
+
+// FindNearbyStoresContext is the same as FindNearbyStores but with a context.
+func (c *Client) FindNearbyStoresContext(ctx context.Context, addr Address, service string) (*NearbyStores, error) {
+	return nearbyStores(ctx, c.cli, addr, service)
+}
+
+// NewStore returns a Store given a store id. See the NewStore function.
+func (c *Client) NewStore(id string, service string, addr Address) (*Store, error) {
+	return newStore(context.Background(), c.cli, id, service, addr)
+}
+
+// NewStoreContext is the same as NewStore but with a context.
+func (c *Client) NewStoreContext(ctx context.Context, id string, service string, addr Address) (*Store, error) {
+	return newStore(ctx, c.cli, id, service, addr)
+}
+
+// InitStore decodes the store profile into any object. See the InitStore function.
+func (c *Client) InitStore(id string, obj interface{}) error {
+	return initStore(context.Background(), c.cli, id, obj)
+}
+
+// InitStoreContext is the same as InitStore but with a context.
+func (c *Client) InitStoreContext(ctx context.Context, id string, obj interface{}) error {
+	return initStore(ctx, c.cli, id, obj)
+}
+
+// SignIn will sign in to a dominos account. See the SignIn function.
+func (c *Client) SignIn(username, password string) (*UserProfile, error) {
+	return signIn(context.Background(), c.cli, username, password)
+}
+
+// SignInContext is the same as SignIn but with a context.
+func (c *Client) SignInContext(ctx context.Context, username, password string) (*UserProfile, error) {
+	return signIn(ctx, c.cli, username, password)
+}
+
+// SignInWithToken signs in using a saved Session. See the SignInWithToken function.
+func (c *Client) SignInWithToken(s *Session) (*UserProfile, error) {
+	return signInWithToken(context.Background(), c.cli, s)
+}
+
+// SignInWithTokenContext is the same as SignInWithToken but with a context.
+func (c *Client) SignInWithTokenContext(ctx context.Context, s *Session) (*UserProfile, error) {
+	return signInWithToken(ctx, c.cli, s)
+}
+
+// ValidateOrder sends an order to the validation endpoint using the client.
+func (c *Client) ValidateOrder(o *Order) error {
+	return c.ValidateOrderContext(context.Background(), o)
+}
+
+// ValidateOrderContext is the same as ValidateOrder but with a context.
+func (c *Client) ValidateOrderContext(ctx context.Context, o *Order) error {
+	return validateOrder(ctx, c.cli, o)
+}
+
+// PlaceOrder sends an order to dominos using the client.
+func (c *Client) PlaceOrder(o *Order) error {
+	return c.PlaceOrderContext(context.Background(), o)
+}
+
+// PlaceOrderContext is the same as PlaceOrder but with a context.
+func (c *Client) PlaceOrderContext(ctx context.Context, o *Order) error {
+	return placeOrder(ctx, c.cli, o)
+}
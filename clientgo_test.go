@@ -0,0 +1,186 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/proxy/power/store/4336/profile", func(w http.ResponseWriter, r *http.Request) {
+		if ua := r.Header.Get("User-Agent"); ua != "test-agent" {
+			t.Errorf("wrong user agent: %q", ua)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"StoreID":"4336","Status":0,"Phone":"555-555-5555"}`)
+	})
+
+	c, err := NewClient(
+		WithBaseURL(srv.URL+"/proxy/"),
+		WithUserAgent("test-agent"),
+		WithLanguage("es"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cli.scheme != "http" {
+		t.Errorf("scheme should come from the base url, got %q", c.cli.scheme)
+	}
+	store, err := c.NewStore("4336", Carryout, testAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.ID != "4336" || store.Phone != "555-555-5555" {
+		t.Error("store was not decoded")
+	}
+	if store.cli != c.cli {
+		t.Error("store should use the client it was created with")
+	}
+	o := store.NewOrder()
+	if o.LanguageCode != "es" {
+		t.Errorf("order should use the client's language, got %q", o.LanguageCode)
+	}
+	if o.cli != c.cli {
+		t.Error("order should use the client it was created with")
+	}
+}
+
+func TestClientOptions_Err(t *testing.T) {
+	for _, opt := range []ClientOption{
+		WithBaseURL("/no/host"),
+		WithBaseURL("%zz"),
+		WithScheme("ftp"),
+		WithHTTPClient(nil),
+		WithLanguage(""),
+	} {
+		if _, err := NewClient(opt); err == nil {
+			t.Error("expected an error from a bad option")
+		}
+	}
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cli.host != orderHost || c.cli.language() != DefaultLang {
+		t.Error("the default client should behave like the package functions")
+	}
+}
+
+func TestClient_Canceled(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("should not have sent a request to %s", r.URL.Path)
+	})
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err = c.NearestStoreContext(ctx, testAddress(), Delivery); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if _, err = c.GetNearbyStoresContext(ctx, testAddress(), Delivery); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	o := &Order{}
+	if err = c.PlaceOrderContext(ctx, o); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if err = c.ValidateOrderContext(ctx, o); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	if o.cli != nil {
+		t.Error("the client should not be stored on the order")
+	}
+}
+
+func TestClient_FindNearbyStores(t *testing.T) {
+	var (
+		mu            sync.Mutex
+		running, most int
+	)
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/power/store-locator", storeLocatorHandlerFunc(t))
+	mux.HandleFunc("/power/store/", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+		time.Sleep(5 * time.Millisecond)
+		if r.URL.Path == "/power/store/4328/profile" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fileHandleFunc(t, "./testdata/store.json")(w, r)
+	})
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := c.FindNearbyStores(testAddress(), Delivery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if most > maxStoreWorkers {
+		t.Errorf("%d stores were requested at once, the limit is %d", most, maxStoreWorkers)
+	}
+	if len(res.Stores) != 12 {
+		t.Errorf("expected 12 stores, got %d", len(res.Stores))
+	}
+	if len(res.Errors) != 1 || res.Errors[0].ID != "4328" {
+		t.Fatalf("expected one error for store 4328, got %v", res.Errors)
+	}
+	for _, s := range res.Stores {
+		if s.cli == nil || s.userAddress == nil {
+			t.Error("stores should be fully initialized")
+		}
+	}
+
+	stores, err := c.GetNearbyStores(testAddress(), Delivery)
+	if err != nil {
+		t.Error("one failed store should not fail the lookup:", err)
+	}
+	if len(stores) != len(res.Stores) {
+		t.Errorf("expected %d stores, got %d", len(res.Stores), len(stores))
+	}
+}
+
+func TestClient_ValidateAddress(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/power/store-locator", storeLocatorHandlerFunc(t))
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	norm, granularity, err := c.ValidateAddress(testAddress())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if granularity != GranularityExact || norm.Zipcode != "20500-0003" {
+		t.Errorf("wrong validated address: %q %+v", granularity, norm)
+	}
+}
@@ -12,6 +12,7 @@ const MenuUpdateTime = 12 * time.Hour
 // CliFlags for the root apizza command.
 type CliFlags struct {
 	Address string
+	Prefer  string
 	Service string// Copyright © 2019 Harrison Brown harrybrown98@gmail.com
 //
 // Licensed under the Apache License, Version 2.0 (the "License");
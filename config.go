@@ -20,6 +20,7 @@ type Config struct {
 		Expiration string `config:"expiration" json:"expiration"`
 	} `config:"card" json:"card"`
 	Service string `config:"service" default:"Delivery" json:"service"`
+	Market  string `config:"market" json:"market"`
 }
 
 // Get a config variable
@@ -34,5 +35,11 @@ func (c *Config) Set(key string, val interface{}) error {
 			return errors.New("service must be either 'Delivery' or 'Carryout'")
 		}
 	}
+	if config.FieldName(c, key) == "Market" {
+		code, _ := val.(string)
+		if _, err := dawg.LookupMarket(code); err != nil {
+			return err
+		}
+	}
 	return config.SetField(c, key, val)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/config"
 	"github.com/harrybrwn/apizza/pkg/errs"
 	"github.com/harrybrwn/apizza/pkg/tests"
@@ -36,11 +37,15 @@ address:
   cityname: "Washington DC"
   state: ""
   zipcode: "20500"
+  unittype: ""
+  unitnumber: ""
+  instructions: ""
 default-address-name: ""
 card:
   number: ""
   expiration: ""
 service: "Carryout"
+market: ""
 `
 
 func TestConfigStruct(t *testing.T) {
@@ -54,6 +59,11 @@ func TestConfigStruct(t *testing.T) {
 	tests.Check(r.Config().Set("name", "not joe"))
 	tests.StrEq(r.Config().Get("Name").(string), "not joe", "wrong value from Config.Get")
 	tests.Check(r.Config().Set("name", "joe"))
+	tests.Check(r.Config().Set("market", "ca"))
+	tests.StrEq(r.Config().Get("market").(string), "ca", "wrong value from Config.Get")
+	if err := r.Config().Set("market", "mars"); err == nil {
+		t.Error("expected an error for an unknown market")
+	}
 }
 
 func TestConfigCmd(t *testing.T) {
@@ -219,6 +229,11 @@ func TestAddressCmd(t *testing.T) {
 	defer r.CleanUp()
 	buf := &bytes.Buffer{}
 	cmd := NewAddAddressCmd(r, buf).(*addAddressCmd)
+	var validated bool
+	cmd.validateAddr = func(addr dawg.Address) (*dawg.StreetAddr, dawg.Granularity, error) {
+		validated = true
+		return dawg.StreetAddrFromAddress(addr), dawg.GranularityExact, nil
+	}
 	tests.Check(cmd.Cmd().ParseFlags([]string{"--new"}))
 
 	inputs := []string{
@@ -227,12 +242,17 @@ func TestAddressCmd(t *testing.T) {
 		"New Providence",
 		"NJ",
 		"07974",
+		"Suite 200",
+		"leave it at the front desk",
 	}
 	for _, in := range inputs {
 		_, err := buf.Write([]byte(in + "\n"))
 		tests.Check(err)
 	}
 	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	if !validated {
+		t.Error("new addresses should be validated")
+	}
 	raw, err := r.DataBase.WithBucket("addresses").Get("testaddress")
 	tests.Check(err)
 	addr, err := obj.FromGob(raw)
@@ -241,6 +261,9 @@ func TestAddressCmd(t *testing.T) {
 	tests.StrEq(addr.CityName, "New Providence", "got wrong city")
 	tests.StrEq(addr.State, "NJ", "go wrong state")
 	tests.StrEq(addr.Zipcode, "07974", "got wrong zip")
+	tests.StrEq(addr.UnitType, "STE", "got wrong unit type")
+	tests.StrEq(addr.UnitNumber, "200", "got wrong unit number")
+	tests.StrEq(addr.DeliveryInstructions, "leave it at the front desk", "got wrong delivery instructions")
 
 	r.Out.Reset()
 	cmd.new = false
@@ -256,3 +279,53 @@ func TestAddressCmd(t *testing.T) {
 		t.Error("should be zero length")
 	}
 }
+
+func TestAddressCmd_Validate(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	buf := &bytes.Buffer{}
+	cmd := NewAddAddressCmd(r, buf).(*addAddressCmd)
+	granularity := dawg.GranularityExact
+	cmd.validateAddr = func(addr dawg.Address) (*dawg.StreetAddr, dawg.Granularity, error) {
+		norm := dawg.StreetAddrFromAddress(addr)
+		norm.StreetName = "MOUNTAIN AVE"
+		norm.Zipcode = "07974-2008"
+		return norm, granularity, nil
+	}
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--new"}))
+
+	for _, tc := range []struct {
+		answer, street, zip string
+		granularity         dawg.Granularity
+	}{
+		{answer: "y", street: "600 MOUNTAIN AVE", zip: "07974-2008", granularity: dawg.GranularityExact},
+		{answer: "n", street: "600 Mountain Ave", zip: "07974", granularity: dawg.GranularityExact},
+		{answer: "", street: "600 Mountain Ave", zip: "07974", granularity: dawg.GranularityStreet},
+	} {
+		r.Out.Reset()
+		buf.Reset()
+		granularity = tc.granularity
+		for _, in := range []string{
+			"testaddress", "600 Mountain Ave", "New Providence", "NJ", "07974",
+			"Apt 4", "", tc.answer,
+		} {
+			buf.WriteString(in + "\n")
+		}
+		tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+		out := r.Out.String()
+		if !strings.Contains(out, "use this address instead? [y/N]") {
+			t.Error("the normalized address should have been offered")
+		}
+		warned := strings.Contains(out, "warning: dominos could only match the address to the street")
+		if warned != (tc.granularity != dawg.GranularityExact) {
+			t.Errorf("wrong granularity warning for %q:\n%s", tc.granularity, out)
+		}
+		raw, err := r.DataBase.WithBucket("addresses").Get("testaddress")
+		tests.Check(err)
+		addr, err := obj.FromGob(raw)
+		tests.Check(err)
+		tests.StrEq(addr.Street, tc.street, "got wrong street")
+		tests.StrEq(addr.Zipcode, tc.zip, "got wrong zip")
+		tests.StrEq(addr.UnitNumber, "4", "the unit should be kept")
+	}
+}
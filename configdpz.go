@@ -81,6 +81,11 @@ by hand or use the 'config' command.`
 var configSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "change variables in the config file",
+	Long: `Change variables in the config file using '<key>=<value>'.
+
+The 'market' variable is the country that dominos is ordered from, use 'us'
+(the default) or 'ca' for Canada. It changes the dominos website used and
+how addresses are read.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return set(args)
 	},
@@ -0,0 +1,202 @@
+package dawg
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Coupon is a coupon from the dominos menu. Coupons are added to an order with
+// Order.AddCoupon and the discount is applied when the order is priced.
+type Coupon struct {
+	Code        string
+	Name        string
+	Description string
+	ImageCode   string
+
+	// Price is the price of the coupon's deal, it can be empty for coupons
+	// that do not have a set price. See Coupon.Cost.
+	Price string
+
+	Tags CouponTags
+
+	// Local is true for coupons that are specific to the store.
+	Local bool
+	// Bundle is true when the coupon is for a group of products.
+	Bundle bool
+}
+
+// CouponTags are the restrictions sent by dominos with each coupon.
+type CouponTags struct {
+	ValidServiceMethods []string
+	EffectiveOn         string
+	ExpiresOn           string
+
+	// MultiSame means that the coupon can be used more than once in an order.
+	MultiSame bool
+	// Combine tells how the coupon can be combined with other coupons.
+	Combine string
+}
+
+// UnmarshalJSON decodes the coupon tags, dominos will sometimes send the
+// valid service methods as a single string instead of a list.
+func (t *CouponTags) UnmarshalJSON(b []byte) error {
+	type tags CouponTags
+	raw := struct {
+		*tags
+		ValidServiceMethods stringList
+	}{tags: (*tags)(t)}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	t.ValidServiceMethods = raw.ValidServiceMethods
+	return nil
+}
+
+// Cost converts the coupon's price to a number.
+func (c *Coupon) Cost() (float64, error) {
+	if c.Price == "" {
+		return 0, fmt.Errorf("coupon %s has no set price", c.Code)
+	}
+	return strconv.ParseFloat(c.Price, 64)
+}
+
+// ValidFor returns true if the coupon can be used with the service method.
+func (c *Coupon) ValidFor(service string) bool {
+	if len(c.Tags.ValidServiceMethods) == 0 {
+		return true
+	}
+	for _, s := range c.Tags.ValidServiceMethods {
+		if s == service {
+			return true
+		}
+	}
+	return false
+}
+
+// Expired returns true if the coupon is not valid at the time given.
+func (c *Coupon) Expired(t time.Time) bool {
+	const layout = "2006-01-02"
+	if start, err := time.Parse(layout, c.Tags.EffectiveOn); err == nil && t.Before(start) {
+		return true
+	}
+	if end, err := time.Parse(layout, c.Tags.ExpiresOn); err == nil && t.After(end.AddDate(0, 0, 1)) {
+		return true
+	}
+	return false
+}
+
+// GetCoupon finds a coupon on the menu given its code.
+func (m *Menu) GetCoupon(code string) (*Coupon, error) {
+	if c, ok := m.Coupons[code]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("could not find coupon '%s'", code)
+}
+
+// OrderCoupon is a coupon that is sent to dominos as part of an order.
+type OrderCoupon struct {
+	Code string `json:"Code"`
+	Qty  int    `json:"Qty"`
+	ID   int    `json:"ID"`
+
+	// Status and StatusItems are only set by dominos after an order has
+	// been priced, see OrderCoupon.Applied.
+	Status      int          `json:"Status,omitempty"`
+	StatusItems []statusItem `json:"StatusItems,omitempty"`
+}
+
+// Applied returns false if dominos rejected the coupon when the order was
+// priced.
+func (c *OrderCoupon) Applied() bool {
+	if c.Status < 0 {
+		return false
+	}
+	for _, item := range c.StatusItems {
+		if item.Code != "CouponFulfilled" {
+			return false
+		}
+	}
+	return true
+}
+
+// ErrCouponUsed is returned when a coupon that cannot be used more than once
+// is added to an order twice.
+var ErrCouponUsed = errors.New("coupon is already in the order")
+
+// AddCoupon will add a coupon from the menu to the order. The coupon must be
+// valid for the order's service method.
+func (o *Order) AddCoupon(c *Coupon) error {
+	if c == nil {
+		return errors.New("cannot add a nil coupon")
+	}
+	if o.ServiceMethod != "" && !c.ValidFor(o.ServiceMethod) {
+		return fmt.Errorf("coupon %s is not valid for %s orders", c.Code, o.ServiceMethod)
+	}
+	if c.Expired(time.Now()) {
+		return fmt.Errorf("coupon %s has expired", c.Code)
+	}
+	if !c.Tags.MultiSame && o.hasCoupon(c.Code) {
+		return ErrCouponUsed
+	}
+	return o.AddCouponCode(c.Code)
+}
+
+// AddCouponCode adds a coupon to the order using only the coupon code. This
+// is useful for coupons that are not on the menu, the coupon will be checked
+// by dominos when the order is priced.
+func (o *Order) AddCouponCode(code string) error {
+	if code == "" {
+		return errors.New("empty coupon code")
+	}
+	for _, c := range o.Coupons {
+		if c.Code == code {
+			c.Qty++
+			o.invalidatePrice()
+			return nil
+		}
+	}
+	o.Coupons = append(o.Coupons, &OrderCoupon{Code: code, Qty: 1, ID: len(o.Coupons) + 1})
+	o.invalidatePrice()
+	return nil
+}
+
+// RemoveCoupon removes a coupon from the order.
+func (o *Order) RemoveCoupon(code string) error {
+	coupons := make([]*OrderCoupon, 0, len(o.Coupons))
+	for _, c := range o.Coupons {
+		if c.Code != code {
+			c.ID = len(coupons) + 1
+			coupons = append(coupons, c)
+		}
+	}
+	if len(coupons) == len(o.Coupons) {
+		return fmt.Errorf("coupon %s not in order", code)
+	}
+	o.Coupons = coupons
+	o.invalidatePrice()
+	return nil
+}
+
+func (o *Order) hasCoupon(code string) bool {
+	for _, c := range o.Coupons {
+		if c.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList is a list of strings that can be decoded from a single string.
+type stringList []string
+
+func (l *stringList) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err == nil {
+		*l = stringList{s}
+		return nil
+	}
+	return json.Unmarshal(b, (*[]string)(l))
+}
@@ -0,0 +1,162 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func menuFromFile(t *testing.T) *Menu {
+	b, err := ioutil.ReadFile("./testdata/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Menu{}
+	if err = json.Unmarshal(b, m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func storeFromFile(t *testing.T) *Store {
+	b, err := ioutil.ReadFile("./testdata/store.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &Store{}
+	if err = json.Unmarshal(b, s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestMenuCoupons(t *testing.T) {
+	m := menuFromFile(t)
+	if len(m.Coupons) == 0 {
+		t.Fatal("no coupons decoded from the menu")
+	}
+	c, err := m.GetCoupon("9174")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// this coupon sends its service methods as a string
+	if !c.ValidFor(Carryout) || c.ValidFor(Delivery) {
+		t.Errorf("wrong service methods: %v", c.Tags.ValidServiceMethods)
+	}
+	if !c.Tags.MultiSame {
+		t.Error("coupon should be usable more than once")
+	}
+	if price, err := c.Cost(); err != nil || price != 7.99 {
+		t.Errorf("wrong coupon price %v (%v)", price, err)
+	}
+	if c, _ = m.GetCoupon("9193"); !c.ValidFor(Delivery) {
+		t.Error("coupon should be valid for delivery")
+	}
+	if _, err = c.Cost(); err == nil {
+		t.Error("coupon without a price should give an error")
+	}
+	if _, err = m.GetCoupon("not-a-coupon"); err == nil {
+		t.Error("expected an error for a missing coupon")
+	}
+	if !c.Expired(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Error("coupon should not be valid before it is effective")
+	}
+}
+
+func TestOrderCoupons(t *testing.T) {
+	m := menuFromFile(t)
+	o := &Order{ServiceMethod: Delivery}
+
+	carryout, _ := m.GetCoupon("9174")
+	if err := o.AddCoupon(carryout); err == nil {
+		t.Error("should not be able to add a carryout coupon to a delivery order")
+	}
+	twist, _ := m.GetCoupon("8149")
+	if err := o.AddCoupon(twist); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.AddCoupon(twist); err != ErrCouponUsed {
+		t.Errorf("expected ErrCouponUsed, got %v", err)
+	}
+	if err := o.AddCouponCode("PROMO"); err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Coupons) != 2 || o.Coupons[1].ID != 2 {
+		t.Fatalf("wrong coupons: %+v", o.Coupons)
+	}
+	if err := o.RemoveCoupon("8149"); err != nil {
+		t.Fatal(err)
+	}
+	if len(o.Coupons) != 1 || o.Coupons[0].Code != "PROMO" || o.Coupons[0].ID != 1 {
+		t.Errorf("coupon was not removed correctly: %+v", o.Coupons)
+	}
+	if err := o.RemoveCoupon("8149"); err == nil {
+		t.Error("expected an error when removing a missing coupon")
+	}
+}
+
+func TestNewOrder_Coupons(t *testing.T) {
+	s := &Store{ID: "4336", userAddress: testAddress()}
+	for _, o := range []*Order{s.NewOrder(), s.MakeOrder("Jane", "Doe", "jane@example.com")} {
+		b, err := json.Marshal(o)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var raw map[string]interface{}
+		if err = json.Unmarshal(b, &raw); err != nil {
+			t.Fatal(err)
+		}
+		if coupons, ok := raw["Coupons"].([]interface{}); !ok || len(coupons) != 0 {
+			t.Errorf("new orders should send an empty list of coupons, got %v", raw["Coupons"])
+		}
+	}
+}
+
+func TestOrderCoupons_Pricing(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := struct{ Order Order }{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+			return
+		}
+		if len(body.Order.Coupons) != 2 || body.Order.Coupons[0].Code != "8149" {
+			t.Errorf("coupons not sent with the order: %+v", body.Order.Coupons)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Status":0,"Order":{"OrderID":"1","Amounts":{"Customer":10.5,"Discount":3.0},
+			"Coupons":[
+				{"Code":"8149","Qty":1,"ID":1,"Status":0,"StatusItems":[{"Code":"CouponFulfilled"}]},
+				{"Code":"BAD","Qty":1,"ID":2,"Status":-1,"StatusItems":[{"Code":"CouponNotFound"}]}
+			]}}`)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := &Order{ServiceMethod: Carryout, cli: c.cli}
+	o.AddCouponCode("8149")
+	o.AddCouponCode("BAD")
+
+	price, err := o.Price()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if price != 10.5 {
+		t.Errorf("wrong price %v", price)
+	}
+	if discount, _ := o.Discount(); discount != 3.0 {
+		t.Errorf("wrong discount %v", discount)
+	}
+	if !o.Coupons[0].Applied() {
+		t.Error("first coupon should have been applied")
+	}
+	if o.Coupons[1].Applied() {
+		t.Error("second coupon should not have been applied")
+	}
+}
@@ -2,6 +2,7 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -78,6 +79,17 @@ func TestOrderAddressConvertion(t *testing.T) {
 	tests.StrEq(res.Zip(), exp.Zip(), "wrong zip code")
 	tests.StrEq(res.StreetNum, exp.StreetNum, "wrong street number")
 	tests.StrEq(res.StreetName, exp.StreetName, "wrong street name")
+
+	addr.UnitType, addr.UnitNumber = "STE", "210"
+	addr.DeliveryInstructions = "call from the lobby"
+	res = StreetAddrFromAddress(addr)
+	tests.StrEq(res.UnitType, "STE", "wrong unit type")
+	tests.StrEq(res.UnitNumber, "210", "wrong unit number")
+	tests.StrEq(res.DeliveryInstructions, addr.DeliveryInstructions, "wrong delivery instructions")
+	user := UserAddressFromAddress(res)
+	if typ, num := user.Unit(); typ != "STE" || num != "210" || user.Instructions() != addr.DeliveryInstructions {
+		t.Error("unit was not copied to the UserAddress")
+	}
 }
 
 func TestParseAddressTable(t *testing.T) {
@@ -111,22 +123,155 @@ func TestParseAddressTable(t *testing.T) {
 	}
 }
 
+func TestParseAddress_Formats(t *testing.T) {
+	var cases = []struct {
+		raw      string
+		expected StreetAddr
+	}{
+		{
+			raw: "1600 Pennsylvania Ave NW, Washington, DC 20500-0003",
+			expected: StreetAddr{StreetNum: "1600", StreetName: "Pennsylvania Ave NW",
+				CityName: "Washington", State: "DC", Zipcode: "20500-0003"},
+		},
+		{
+			raw: "12 N. Main Street Apt 4B\nSpringfield, il 62701",
+			expected: StreetAddr{StreetNum: "12", StreetName: "N. Main Street",
+				CityName: "Springfield", State: "IL", Zipcode: "62701",
+				UnitType: "APT", UnitNumber: "4B"},
+		},
+		{
+			raw: "350 5th Ave Suite 3400 New York NY 10118",
+			expected: StreetAddr{StreetNum: "350", StreetName: "5th Ave",
+				CityName: "New York", State: "NY", Zipcode: "10118",
+				UnitType: "STE", UnitNumber: "3400"},
+		},
+		{
+			raw: "12345 Broadway, #12, Los Angeles, CA 900120000",
+			expected: StreetAddr{StreetNum: "12345", StreetName: "Broadway",
+				CityName: "Los Angeles", State: "CA", Zipcode: "90012-0000",
+				UnitType: "#", UnitNumber: "12"},
+		},
+		{
+			raw: "221 Main St St Louis, MO 63101",
+			expected: StreetAddr{StreetNum: "221", StreetName: "Main St",
+				CityName: "St Louis", State: "MO", Zipcode: "63101"},
+		},
+		{
+			raw: "789 N Lot Rd, Springfield, IL 62701",
+			expected: StreetAddr{StreetNum: "789", StreetName: "N Lot Rd",
+				CityName: "Springfield", State: "IL", Zipcode: "62701"},
+		},
+		{
+			raw: "3 Old Floor Ln Springfield IL 62701",
+			expected: StreetAddr{StreetNum: "3", StreetName: "Old Floor Ln",
+				CityName: "Springfield", State: "IL", Zipcode: "62701"},
+		},
+		{
+			raw: "40 Elm St Apt B, Springfield, IL 62701",
+			expected: StreetAddr{StreetNum: "40", StreetName: "Elm St",
+				CityName: "Springfield", State: "IL", Zipcode: "62701",
+				UnitType: "APT", UnitNumber: "B"},
+		},
+	}
+	for _, tc := range cases {
+		addr, err := ParseAddress(tc.raw)
+		if err != nil {
+			t.Errorf("%q: %v", tc.raw, err)
+			continue
+		}
+		tc.expected.Street = tc.expected.StreetNum + " " + tc.expected.StreetName
+		if *addr != tc.expected {
+			t.Errorf("%q:\ngot  %+v\nwant %+v", tc.raw, *addr, tc.expected)
+		}
+	}
+
+	var errCases = []struct{ raw, part string }{
+		{"1600 Pennsylvania Ave, Washington, DC", "zip code"},
+		{"1600 Pennsylvania Ave, Washington, 20500", "state"},
+		{"Pennsylvania Ave, Washington, DC 20500", "street number"},
+		{"1600 Broadway Washington DC 20500", "street"},
+		{"1600 Main St Apt, Washington, DC 20500", "unit"},
+		{"1600 Main St Apt Xyz, Washington, DC 20500", "unit"},
+		{"1600 Main St, , DC 20500", "city"},
+		{"", "address"},
+	}
+	for _, tc := range errCases {
+		_, err := ParseAddress(tc.raw)
+		e, ok := err.(*AddressError)
+		if !ok {
+			t.Errorf("%q: expected an *AddressError, got %v", tc.raw, err)
+			continue
+		}
+		if e.Part != tc.part {
+			t.Errorf("%q: got error for the %s, want %s", tc.raw, e.Part, tc.part)
+		}
+	}
+	if _, err := ParseAddress(" \n "); err == nil || err.Error() != "empty address" {
+		t.Errorf("wrong error for an empty address: %v", err)
+	}
+}
+
+func TestParseUnit(t *testing.T) {
+	for _, tc := range []struct{ in, typ, num string }{
+		{"Suite 200", "STE", "200"},
+		{"apt. 4B", "APT", "4B"},
+		{"#12", "#", "12"},
+		{"4", "", "4"},
+	} {
+		typ, num, ok := ParseUnit(tc.in)
+		if !ok || typ != tc.typ || num != tc.num {
+			t.Errorf("ParseUnit(%q) = %q, %q, %v; want %q, %q", tc.in, typ, num, ok, tc.typ, tc.num)
+		}
+	}
+	for _, in := range []string{"", "Penthouse", "Apt", "Apt Rd", "Suite 200 North"} {
+		if _, _, ok := ParseUnit(in); ok {
+			t.Errorf("ParseUnit(%q) should fail", in)
+		}
+	}
+}
+
+func TestValidateAddress(t *testing.T) {
+	cli, mux, server := testServer()
+	defer server.Close()
+	defer swapClientWith(cli)()
+	mux.HandleFunc("/power/store-locator", storeLocatorHandlerFunc(t))
+
+	addr := testAddress()
+	addr.UnitType, addr.UnitNumber = "STE", "100"
+	norm, granularity, err := ValidateAddress(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if granularity != GranularityExact {
+		t.Errorf("expected an exact match, got %q", granularity)
+	}
+	if norm.Zipcode != "20500-0003" || norm.StreetName != "PENNSYLVANIA AVE NW" {
+		t.Errorf("wrong normalized address: %+v", norm)
+	}
+	if norm.UnitNumber != "100" {
+		t.Error("unit number should be kept when dominos does not return one")
+	}
+	if _, _, err = ValidateAddress(nil); err == nil {
+		t.Error("expected an error for a nil address")
+	}
+}
+
 func TestNetworking_Err(t *testing.T) {
 	t.Skip("this test takes way too long")
 	tests.InitHelpers(t)
 	defer swapclient(10)()
-	_, err := orderClient.get("/", nil)
+	_, err := orderClient.get(context.Background(), "/", nil)
 	tests.Exp(err)
-	_, err = orderClient.get("/invalid path", nil)
+	_, err = orderClient.get(context.Background(), "/invalid path", nil)
 	tests.Exp(err)
-	b, err := orderClient.post("/invalid path", nil, bytes.NewReader(make([]byte, 1)))
+	b, err := orderClient.post(context.Background(), "/invalid path", nil, bytes.NewReader(make([]byte, 1)))
 	tests.Exp(err)
 	if len(b) != 0 {
 		t.Error("expected zero length response")
 	}
-	_, err = orderClient.post("invalid path", nil, bytes.NewReader(nil))
+	_, err = orderClient.post(context.Background(), "invalid path", nil, bytes.NewReader(nil))
 	tests.Exp(err)
-	_, err = orderClient.post("/power/price-order", nil, bytes.NewReader([]byte{}))
+	_, err = orderClient.post(context.Background(), "/power/price-order", nil, bytes.NewReader([]byte{}))
 	tests.Exp(err)
 	cli := &client{
 		Client: &http.Client{
@@ -138,12 +283,12 @@ func TestNetworking_Err(t *testing.T) {
 			Timeout: time.Second,
 		},
 	}
-	resp, err := cli.get("/power/store/4336/profile", nil)
+	resp, err := cli.get(context.Background(), "/power/store/4336/profile", nil)
 	tests.Exp(err)
 	if resp != nil {
 		t.Error("should not have gotten any response data")
 	}
-	b, err = cli.post("/invalid path", nil, bytes.NewReader(make([]byte, 1)))
+	b, err = cli.post(context.Background(), "/invalid path", nil, bytes.NewReader(make([]byte, 1)))
 	tests.Exp(err)
 	if b != nil {
 		t.Error("expected zero length response")
@@ -179,7 +324,7 @@ func TestDominosErrors(t *testing.T) {
 		OrderID: "",
 		Address: testAddress(),
 	}
-	resp, err := orderClient.post("/power/price-order", nil, order.raw())
+	resp, err := orderClient.post(context.Background(), "/power/price-order", nil, order.raw())
 	if err != nil {
 		t.Error(err)
 	}
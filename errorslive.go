@@ -178,6 +178,12 @@ func (e *errorpair) Error() string {
 	return fmt.Sprintf("error 1. %s\nerror 2. %s", e.e1.Error(), e.e2.Error())
 }
 
+// Unwrap returns the first error so that errors.Is and errors.As can
+// see the error that actually caused the failure.
+func (e *errorpair) Unwrap() error {
+	return e.e1
+}
+
 func eatint(n int, e error) error {
 	return e
 }
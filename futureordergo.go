@@ -0,0 +1,69 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// FutureTimeFormat is the layout of an order's FutureOrderTime.
+	FutureTimeFormat = "2006-01-02 15:04:05"
+
+	// maxFutureOrderDays is how far ahead an order can be scheduled.
+	maxFutureOrderDays = 21
+)
+
+// SetFutureTime schedules the order to be delivered or ready for pickup at
+// a later time. The time is checked against the store's hours for the
+// order's service method and how far ahead the store needs scheduled orders
+// to be made, see Store.ValidateFutureTime.
+func (o *Order) SetFutureTime(s *Store, t time.Time) error {
+	if s == nil {
+		return errors.New("cannot schedule an order without a store")
+	}
+	if err := s.ValidateFutureTime(o.ServiceMethod, t); err != nil {
+		return err
+	}
+	o.FutureOrderTime = t.In(s.location(t)).Format(FutureTimeFormat)
+	return nil
+}
+
+// FutureTime returns the time that the order is scheduled for in the store's
+// time zone. The last return value is false if the order is not scheduled.
+func (o *Order) FutureTime(s *Store) (time.Time, bool) {
+	if o.FutureOrderTime == "" {
+		return time.Time{}, false
+	}
+	loc := time.Local
+	if s != nil {
+		loc = s.Location()
+	}
+	t, err := time.ParseInLocation(FutureTimeFormat, o.FutureOrderTime, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ValidateFutureTime will return an error if an order for a service method
+// cannot be scheduled for some time. Scheduled orders have to be made at
+// least FutureOrderDelayInHours ahead, at most three weeks ahead, and while
+// the store is open.
+func (s *Store) ValidateFutureTime(service string, t time.Time) error {
+	return s.validateFutureTime(service, t, time.Now())
+}
+
+func (s *Store) validateFutureTime(service string, t, now time.Time) error {
+	lead := time.Duration(s.FutureOrderDelayInHours) * time.Hour
+	if !t.After(now.Add(lead)) {
+		if lead == 0 {
+			return errors.New("scheduled orders must be in the future")
+		}
+		return fmt.Errorf("the store needs orders to be scheduled at least %d hour(s) ahead", s.FutureOrderDelayInHours)
+	}
+	if t.After(now.AddDate(0, 0, maxFutureOrderDays)) {
+		return fmt.Errorf("orders cannot be scheduled more than %d days ahead", maxFutureOrderDays)
+	}
+	return s.CheckOpen(service, t)
+}
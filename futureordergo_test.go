@@ -0,0 +1,61 @@
+package dawg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStore_ValidateFutureTime(t *testing.T) {
+	store := storeFromFile(t)
+	if store.FutureOrderDelayInHours != 1 {
+		t.Errorf("wrong future order delay: %d", store.FutureOrderDelayInHours)
+	}
+	zone := time.FixedZone("", -4*60*60)
+	now := time.Date(2020, time.April, 10, 23, 45, 0, 0, zone) // friday
+
+	for _, tc := range []struct {
+		service string
+		at      time.Time
+		err     string
+	}{
+		{Carryout, time.Date(2020, time.April, 11, 12, 15, 0, 0, zone), ""},
+		{Delivery, time.Date(2020, time.April, 11, 11, 0, 0, 0, zone), ""},
+		{Delivery, now.Add(30 * time.Minute), "at least 1 hour"},
+		{Delivery, now.Add(-time.Hour), "at least 1 hour"},
+		{Carryout, now.AddDate(0, 0, 30), "more than 21 days"},
+		{Carryout, time.Date(2020, time.April, 11, 2, 0, 0, 0, zone), "closed for carryout"},
+	} {
+		err := store.validateFutureTime(tc.service, tc.at, now)
+		if tc.err == "" && err != nil {
+			t.Errorf("%s at %v: %v", tc.service, tc.at, err)
+		} else if tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("%s at %v: expected an error with %q, got %v", tc.service, tc.at, tc.err, err)
+		}
+	}
+
+	at, err := store.NextOpening(Delivery, time.Now().Add(48*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	at = at.Truncate(time.Minute)
+	o := &Order{ServiceMethod: Delivery}
+	if err = o.SetFutureTime(store, at); err != nil {
+		t.Fatal(err)
+	}
+	if o.FutureOrderTime != at.In(zone).Format(FutureTimeFormat) {
+		t.Errorf("wrong future order time: %s", o.FutureOrderTime)
+	}
+	if ft, ok := o.FutureTime(store); !ok || !ft.Equal(at) {
+		t.Errorf("wrong future time: %v", ft)
+	}
+	if !strings.Contains(OrderToJSON(o), `"FutureOrderTime"`) {
+		t.Error("future order time should be sent to dominos")
+	}
+	if strings.Contains(OrderToJSON(&Order{}), `"FutureOrderTime"`) {
+		t.Error("orders that are not scheduled should not send a future order time")
+	}
+	if err = o.SetFutureTime(store, time.Now()); err == nil {
+		t.Error("expected an error for an order scheduled now")
+	}
+}
@@ -1,8 +1,6 @@
 package dawg
 
 import (
-	"errors"
-	"fmt"
 	"strings"
 )
 
@@ -89,7 +87,8 @@ type Product struct {
 	// toppings, sides, sizes, or flavors.
 	ProductType string
 
-	opts map[string]interface{}
+	opts  map[string]interface{}
+	sides map[string]int
 }
 
 // Options returns a map of the Product's options.
@@ -115,9 +114,9 @@ func (p *Product) AddTopping(code, side, amount string) error {
 	if p.opts == nil {
 		p.opts = make(map[string]interface{})
 	}
-	top := makeTopping(side, amount, p.optionQtys())
-	if top == nil {
-		return fmt.Errorf("could not make a %s topping", code)
+	top, err := makeTopping(side, amount, p.optionQtys())
+	if err != nil {
+		return &ToppingError{Topping: code, Item: p.Code, Err: err}
 	}
 	p.opts[code] = top
 	return nil
@@ -151,7 +150,7 @@ func (p *Product) optionQtys() (optqtys []string) {
 		}
 		return optqtys
 	}
-	return nil
+	return productOptQtys[p.ProductType]
 }
 
 // Variant is a structure that represents a base component of the Dominos menu.
@@ -169,8 +168,14 @@ type Variant struct {
 	// true if the variant is prepared by dominos
 	Prepared bool
 
+	// SizeCode and FlavorCode are the codes of the variant's size and
+	// flavor, see Menu.Sizes and Menu.Flavors.
+	SizeCode   string
+	FlavorCode string
+
 	product *Product
 	opts    map[string]interface{}
+	sides   map[string]int
 }
 
 // Options returns a map of the Variant's options.
@@ -205,9 +210,9 @@ func (v *Variant) AddTopping(code, side, amount string) error {
 		qtys = nil
 	}
 
-	top := makeTopping(side, amount, qtys)
-	if top == nil {
-		return fmt.Errorf("could not make %s topping", code)
+	top, err := makeTopping(side, amount, qtys)
+	if err != nil {
+		return &ToppingError{Topping: code, Item: v.Code, Err: err}
 	}
 	v.opts[code] = top
 	return nil
@@ -215,7 +220,10 @@ func (v *Variant) AddTopping(code, side, amount string) error {
 
 // Category returns the product category. see Item
 func (v *Variant) Category() string {
-	return v.GetProduct().Category()
+	if v.product == nil {
+		return ""
+	}
+	return v.product.Category()
 }
 
 // GetProduct will return the set of variants (Product) that the variant
@@ -241,6 +249,10 @@ func (v *Variant) FindProduct(m *Menu) *Product {
 }
 
 // PreConfiguredProduct is pre-configured product.
+//
+// A pre-configured product is a variant with some toppings already on it. To
+// customize the product it needs to be initialized with its variant, see
+// PreConfiguredProduct.FindVariant.
 type PreConfiguredProduct struct {
 	ItemCommon
 
@@ -253,29 +265,86 @@ type PreConfiguredProduct struct {
 	// Size is the size name of the product. It's not a code or anything, its
 	// more for user level stuff.
 	Size string `json:"Size"`
+
+	// ReferencedProductCode is the code of the variant that the product is
+	// made from.
+	ReferencedProductCode string `json:"ReferencedProductCode"`
+
+	// ProductType is the category of the product's variant. It is set when
+	// the product is initialized with its variant so that the category is
+	// kept when the menu is stored.
+	ProductType string `json:"ProductType,omitempty"`
+
+	variant *Variant
+	opts    map[string]interface{}
 }
 
-// Options returns a map of the Variant's options.
+// Options returns a map of the product's options. These are the default
+// toppings of the product's variant with the pre-configured options on top.
 func (pc *PreConfiguredProduct) Options() map[string]interface{} {
-	var opts = map[string]interface{}{}
+	if pc.opts == nil {
+		pc.opts = make(map[string]interface{})
+	}
+	defaults := []string{pc.Opts}
+	if pc.variant != nil {
+		variantDefaults, _ := pc.variant.Tags["DefaultToppings"].(string)
+		defaults = append(defaults, variantDefaults)
+	}
 
-	codes, amounts, n := splitDefaults(pc.Opts)
-	for i := 0; i < n; i++ {
-		opts[codes[i]] = map[string]string{ToppingFull: amounts[i]}
+	for _, defs := range defaults {
+		codes, amounts, n := splitDefaults(defs)
+		for i := 0; i < n; i++ {
+			// if the default topping is not already in the options then add it
+			if _, ok := pc.opts[codes[i]]; !ok {
+				pc.opts[codes[i]] = map[string]string{ToppingFull: amounts[i]}
+			}
+		}
 	}
-	return opts
+	return pc.opts
 }
 
-// AddTopping adds a topping to the product.
+// AddTopping adds a topping to the product. If the product has been
+// initialized with its variant then the amount is checked against the
+// amounts allowed for the variant's product.
 func (pc *PreConfiguredProduct) AddTopping(code, cover, amnt string) error {
-	// TODO: finish this
-	return errors.New("not implimented")
+	var qtys []string
+	if pc.variant != nil && pc.variant.product != nil {
+		qtys = pc.variant.product.optionQtys()
+	}
+	top, err := makeTopping(cover, amnt, qtys)
+	if err != nil {
+		return &ToppingError{Topping: code, Item: pc.Code, Err: err}
+	}
+	pc.Options()[code] = top
+	return nil
 }
 
 // Category returns the product category. see Item
 func (pc *PreConfiguredProduct) Category() string {
-	// TODO: finish this
-	return "n/a"
+	if pc.variant == nil {
+		return pc.ProductType
+	}
+	return pc.variant.Category()
+}
+
+// GetVariant will return the variant that the product is made from, this will
+// be nil if the product has not been initialized with a menu.
+func (pc *PreConfiguredProduct) GetVariant() *Variant {
+	return pc.variant
+}
+
+// FindVariant will initialize the product with the variant that it is made
+// from and return that variant. Returns nil if the variant is not found.
+func (pc *PreConfiguredProduct) FindVariant(m *Menu) *Variant {
+	if pc.variant != nil {
+		return pc.variant
+	}
+	if v, ok := m.Variants[pc.ReferencedProductCode]; ok {
+		pc.variant = m.initVariant(v)
+		pc.ProductType = pc.variant.Category()
+		return pc.variant
+	}
+	return nil
 }
 
 func splitDefaults(defs string) (keys, vals []string, n int) {
@@ -0,0 +1,59 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/errs"
+)
+
+// LastOrderKey is the database key for the most recently placed order.
+const LastOrderKey = "last_placed_order"
+
+// ErrNoPlacedOrder is returned when no order has been placed yet.
+var ErrNoPlacedOrder = errors.New("no orders have been placed")
+
+// PlacedOrder is a record of an order that has been sent to dominos. It
+// has everything needed to find the order in the dominos order tracker.
+type PlacedOrder struct {
+	Name       string    `json:"name"`
+	Phone      string    `json:"phone"`
+	StoreID    string    `json:"store_id"`
+	TrackingID string    `json:"tracking_id"`
+	Service    string    `json:"service"`
+	Placed     time.Time `json:"placed"`
+}
+
+// Tracker returns a dawg.Tracker for the placed order.
+func (p *PlacedOrder) Tracker() *dawg.Tracker {
+	return dawg.NewTracker(p.Phone, p.TrackingID)
+}
+
+// SaveLastOrder will store a record of an order that has just been placed.
+func SaveLastOrder(o *dawg.Order, db cache.Putter) error {
+	raw, err := json.Marshal(&PlacedOrder{
+		Name:       o.Name(),
+		Phone:      o.Phone,
+		StoreID:    o.StoreID,
+		TrackingID: o.TrackingID(),
+		Service:    o.ServiceMethod,
+		Placed:     time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return db.Put(LastOrderKey, raw)
+}
+
+// GetLastOrder gets the record of the last order that was placed.
+func GetLastOrder(db cache.Getter) (*PlacedOrder, error) {
+	raw, err := db.Get(LastOrderKey)
+	if raw == nil {
+		return nil, ErrNoPlacedOrder
+	}
+	p := &PlacedOrder{}
+	return p, errs.Pair(err, json.Unmarshal(raw, p))
+}
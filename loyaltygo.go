@@ -0,0 +1,105 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// LoyaltyCoupon is a coupon that can be bought with loyalty points.
+type LoyaltyCoupon struct {
+	CouponCode string
+	// PointValue is the number of points needed for the coupon.
+	PointValue int
+	BaseCoupon bool
+	// LimitPerOrder is the number of times that the coupon can be used in
+	// one order, see LoyaltyCoupon.Limit.
+	LimitPerOrder string
+}
+
+// Limit returns the number of times that the coupon can be used in one order.
+// Zero means that there is no limit.
+func (c *LoyaltyCoupon) Limit() int {
+	n, err := strconv.Atoi(c.LimitPerOrder)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// ErrNotEnoughPoints is returned when a loyalty coupon costs more points than
+// the account has.
+var ErrNotEnoughPoints = errors.New("not enough loyalty points")
+
+// Coupon finds one of the loyalty coupons by its code.
+func (l *CustomerLoyalty) Coupon(code string) (*LoyaltyCoupon, error) {
+	for i := range l.LoyaltyCoupons {
+		if l.LoyaltyCoupons[i].CouponCode == code {
+			return &l.LoyaltyCoupons[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no loyalty coupon with code '%s'", code)
+}
+
+// Affordable returns the loyalty coupons that can be bought with the vested
+// point balance.
+func (l *CustomerLoyalty) Affordable() []*LoyaltyCoupon {
+	coupons := make([]*LoyaltyCoupon, 0, len(l.LoyaltyCoupons))
+	for i := range l.LoyaltyCoupons {
+		if l.LoyaltyCoupons[i].PointValue <= l.VestedPointBalance {
+			coupons = append(coupons, &l.LoyaltyCoupons[i])
+		}
+	}
+	return coupons
+}
+
+// PointsUsed returns the number of points that are spent by the loyalty
+// coupons in an order.
+func (l *CustomerLoyalty) PointsUsed(o *Order) int {
+	points := 0
+	for _, c := range o.Coupons {
+		if lc, err := l.Coupon(c.Code); err == nil {
+			points += lc.PointValue * c.Qty
+		}
+	}
+	return points
+}
+
+// RedeemLoyaltyCoupon spends loyalty points on a coupon and adds it to the
+// order. The account must have enough vested points for every loyalty coupon
+// in the order and the coupon's per-order limit cannot be exceeded.
+//
+// Loyalty coupons can only be used by the account that has the points so the
+// order will be sent as the user, see Order.SetCustomer.
+func (u *UserProfile) RedeemLoyaltyCoupon(o *Order, code string) error {
+	loyalty, err := u.getLoyalty()
+	if err != nil {
+		return err
+	}
+	coupon, err := loyalty.Coupon(code)
+	if err != nil {
+		return err
+	}
+	if loyalty.PointsUsed(o)+coupon.PointValue > loyalty.VestedPointBalance {
+		return ErrNotEnoughPoints
+	}
+	if limit := coupon.Limit(); limit > 0 {
+		for _, c := range o.Coupons {
+			if c.Code == code && c.Qty >= limit {
+				return fmt.Errorf("coupon %s can only be used %d time(s) per order", code, limit)
+			}
+		}
+	}
+	if err = o.AddCouponCode(code); err != nil {
+		return err
+	}
+	o.SetCustomer(u)
+	return nil
+}
+
+// SetCustomer sets the account that the order will be sent as. This is
+// needed for orders that use saved cards or loyalty points.
+func (o *Order) SetCustomer(u *UserProfile) {
+	o.CustomerID = u.ID
+	o.cli = u.cli
+}
@@ -0,0 +1,53 @@
+package dawg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRedeemLoyaltyCoupon(t *testing.T) {
+	loyalty := &CustomerLoyalty{}
+	err := json.Unmarshal([]byte(`{
+		"VestedPointBalance": 130,
+		"LoyaltyCoupons": [
+			{"CouponCode": "8012", "PointValue": 60, "BaseCoupon": true, "LimitPerOrder": "1"},
+			{"CouponCode": "8013", "PointValue": 20, "BaseCoupon": false, "LimitPerOrder": ""},
+			{"CouponCode": "8014", "PointValue": 200, "BaseCoupon": false, "LimitPerOrder": "1"}
+		]}`), loyalty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(loyalty.Affordable()); n != 2 {
+		t.Errorf("expected 2 affordable coupons, got %d", n)
+	}
+
+	user := &UserProfile{ID: "user", cli: orderClient, loyaltyData: loyalty}
+	o := &Order{}
+	if err = user.RedeemLoyaltyCoupon(o, "8012"); err != nil {
+		t.Fatal(err)
+	}
+	if o.CustomerID != "user" {
+		t.Error("order should be sent as the user with the points")
+	}
+	if err = user.RedeemLoyaltyCoupon(o, "8012"); err == nil {
+		t.Error("should not be able to use the coupon more than its limit")
+	}
+	if err = user.RedeemLoyaltyCoupon(o, "8014"); err != ErrNotEnoughPoints {
+		t.Errorf("expected ErrNotEnoughPoints, got %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err = user.RedeemLoyaltyCoupon(o, "8013"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if used := loyalty.PointsUsed(o); used != 120 {
+		t.Errorf("expected 120 points used, got %d", used)
+	}
+	// 120 points have been used so there are not enough for another
+	if err = user.RedeemLoyaltyCoupon(o, "8013"); err != ErrNotEnoughPoints {
+		t.Errorf("expected ErrNotEnoughPoints, got %v", err)
+	}
+	if err = user.RedeemLoyaltyCoupon(o, "0000"); err == nil {
+		t.Error("expected an error for a coupon that is not a loyalty coupon")
+	}
+}
@@ -3,6 +3,8 @@ package data
 import (
 	"bytes"
 	"log"
+	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,6 +56,48 @@ func TestDBManagement(t *testing.T) {
 	tests.Check(db.Destroy())
 }
 
+func TestLastOrder(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+
+	_, err := GetLastOrder(db)
+	if err != ErrNoPlacedOrder {
+		t.Errorf("expected ErrNoPlacedOrder, got %v", err)
+	}
+	o := &dawg.Order{Phone: "555-555-5555", StoreID: "4336", OrderID: "abc"}
+	o.SetName("test_order")
+	tests.Check(SaveLastOrder(o, db))
+
+	buf := &bytes.Buffer{}
+	tests.Check(PrintOrders(db, buf, false, ""))
+	tests.Compare(t, buf.String(), "No orders saved.\n")
+
+	placed, err := GetLastOrder(db)
+	tests.Check(err)
+	tests.StrEq(placed.Name, "test_order", "wrong order name")
+	tests.StrEq(placed.TrackingID, "abc", "wrong tracking id")
+	tr := placed.Tracker()
+	tests.StrEq(tr.Phone, "5555555555", "tracker should only use the phone number's digits")
+	tests.StrEq(tr.OrderID, "abc", "wrong tracker order id")
+}
+
+func TestSession(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+
+	_, err := GetSession(db)
+	if err != ErrNoSession {
+		t.Errorf("expected ErrNoSession, got %v", err)
+	}
+	tests.Check(SaveSession(&dawg.Session{AccessToken: "access", RefreshToken: "refresh"}, db))
+	s, err := GetSession(db)
+	tests.Check(err)
+	tests.StrEq(s.AccessToken, "access", "wrong access token")
+	tests.StrEq(s.RefreshToken, "refresh", "wrong refresh token")
+}
+
 func TestPrintOrders(t *testing.T) {
 	tests.InitHelpers(t)
 	var err error
@@ -75,6 +119,71 @@ func TestPrintOrders(t *testing.T) {
 	tests.Compare(t, buf.String(), "Your Orders:\n  test_order -  10SCREEN, \n")
 }
 
+func TestMenuChanges(t *testing.T) {
+	tests.InitHelpers(t)
+	db := cmdtest.TempDB()
+	defer func() { tests.Check(db.Destroy()) }()
+
+	updates, err := GetMenuUpdates(db)
+	tests.Check(err)
+	if len(updates) != 0 {
+		t.Error("should not have any menu updates yet")
+	}
+
+	o := &dawg.Order{Products: []*dawg.OrderProduct{
+		{ItemCommon: dawg.ItemCommon{Code: "10SCREEN"}, Opts: map[string]interface{}{"X": nil, "P": nil}},
+	}}
+	o.SetName("test_order")
+	tests.Check(SaveOrder(o, new(bytes.Buffer), db))
+	tests.Check(db.Put(OrderPrefix+"broken", []byte("{not an order")))
+
+	oldMenu := &dawg.Menu{
+		ID:       "4336",
+		Variants: map[string]*dawg.Variant{"10SCREEN": {Price: "9.99"}, "12SCREEN": {Price: "11.99"}},
+		Toppings: map[string]map[string]dawg.Topping{"Pizza": {"X": {}, "P": {}}},
+	}
+	newMenu := &dawg.Menu{
+		ID:       "4336",
+		Variants: map[string]*dawg.Variant{"12SCREEN": {Price: "12.99"}},
+		Toppings: map[string]map[string]dawg.Topping{"Pizza": {"X": {}}},
+	}
+	buf := new(bytes.Buffer)
+	c := &generalMenuCacher{db: db, warn: buf}
+	logs := new(bytes.Buffer)
+	log.SetOutput(logs)
+	defer log.SetOutput(os.Stderr)
+	for i := 0; i < maxMenuUpdates+2; i++ {
+		tests.Check(c.recordChanges(oldMenu, newMenu))
+	}
+	tests.Compare(t, buf.String(), strings.Repeat(
+		"warning: the order 'test_order' has items that are no longer on the menu: 10SCREEN, P\n", maxMenuUpdates+2))
+	if !strings.Contains(logs.String(), "could not check order 'broken'") {
+		t.Error("orders that cannot be decoded should be logged and skipped")
+	}
+
+	updates, err = GetMenuUpdates(db)
+	tests.Check(err)
+	if len(updates) != maxMenuUpdates {
+		t.Fatalf("expected %d updates, got %d", maxMenuUpdates, len(updates))
+	}
+	u := updates[len(updates)-1]
+	tests.StrEq(u.StoreID, "4336", "wrong store id")
+	if len(u.RemovedVariants) != 1 || u.RemovedVariants[0] != "10SCREEN" {
+		t.Errorf("wrong removed variants: %v", u.RemovedVariants)
+	}
+	if len(u.PriceChanges) != 1 || u.PriceChanges[0].New != "12.99" {
+		t.Errorf("wrong price changes: %v", u.PriceChanges)
+	}
+
+	buf.Reset()
+	tests.Check(c.recordChanges(newMenu, newMenu))
+	updates, err = GetMenuUpdates(db)
+	tests.Check(err)
+	if len(updates) != maxMenuUpdates || buf.Len() != 0 {
+		t.Error("nothing should be recorded when the menu does not change")
+	}
+}
+
 func TestMenuCacherJSON(t *testing.T) {
 	t.Skip()
 	tests.InitHelpers(t)
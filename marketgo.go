@@ -0,0 +1,230 @@
+package dawg
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Market is a country that dominos operates in. Each market has its own api
+// host, languages, currency, and address format.
+type Market struct {
+	// Code is the short name of the market, for example "us" or "ca".
+	Code string
+	// Name is the full name of the market's country.
+	Name string
+	// Host is the host that the market's api requests are sent to.
+	Host string
+	// TrackerHost is the host of the market's order tracker.
+	TrackerHost string
+	// TrackerName is the name that the order tracker uses for the market,
+	// for example "UNITED_STATES".
+	TrackerName string
+	// Languages are the language codes that the market supports, the
+	// first one is the default.
+	Languages []string
+	// Currency is the ISO 4217 code of the currency that prices are shown
+	// in, see FormatPrice.
+	Currency string
+
+	// RegionName is what an address's state is called in the market
+	// ("state" or "province") and PostalName is what the postal code is
+	// called ("zip code" or "postal code").
+	RegionName string
+	PostalName string
+	// Regions are the region codes that addresses in the market can
+	// use. Any two letter code is allowed if it is empty.
+	Regions []string
+
+	// postal parses the postal code at the end of a list of words and
+	// returns the normalized code along with the number of words it used.
+	postal func(words []string) (code string, n int)
+}
+
+var (
+	// MarketUS is the United States market, it is the default market.
+	MarketUS = &Market{
+		Code:        "us",
+		Name:        "United States",
+		Host:        orderHost,
+		TrackerHost: "tracker.dominos.com",
+		TrackerName: "UNITED_STATES",
+		Languages:   []string{"en", "es"},
+		Currency:    "USD",
+		RegionName:  "state",
+		PostalName:  "zip code",
+		postal:      usPostalCode,
+	}
+
+	// MarketCA is the Canadian market.
+	MarketCA = &Market{
+		Code:        "ca",
+		Name:        "Canada",
+		Host:        "order.dominos.ca",
+		TrackerHost: "tracker.dominos.ca",
+		TrackerName: "CANADA",
+		Languages:   []string{"en", "fr"},
+		Currency:    "CAD",
+		RegionName:  "province",
+		PostalName:  "postal code",
+		Regions: []string{
+			"AB", "BC", "MB", "NB", "NL", "NS", "NT",
+			"NU", "ON", "PE", "QC", "SK", "YT",
+		},
+		postal: caPostalCode,
+	}
+
+	markets = map[string]*Market{
+		MarketUS.Code: MarketUS,
+		MarketCA.Code: MarketCA,
+	}
+)
+
+// LookupMarket finds a market by its code. Case is ignored and an empty code
+// gives the default market.
+func LookupMarket(code string) (*Market, error) {
+	if code == "" {
+		return MarketUS, nil
+	}
+	if m, ok := markets[strings.ToLower(code)]; ok {
+		return m, nil
+	}
+	return nil, fmt.Errorf("unknown market '%s' (use one of: %s)", code, strings.Join(MarketCodes(), ", "))
+}
+
+// MarketCodes returns the codes of all the supported markets.
+func MarketCodes() []string {
+	codes := make([]string, 0, len(markets))
+	for code := range markets {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// SetMarket changes the market used by all of the package level functions.
+// It is not safe to call while other requests are being made.
+func SetMarket(m *Market) {
+	if m == nil {
+		m = MarketUS
+	}
+	orderClient.market = m
+	orderClient.host = m.Host
+	orderClient.lang = m.DefaultLanguage()
+}
+
+// DefaultMarket returns the market used by the package level functions.
+func DefaultMarket() *Market {
+	return orderClient.getMarket()
+}
+
+// currencySymbols are the symbols that prices are shown with for each
+// currency code.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"CAD": "$",
+}
+
+// CurrencySymbol returns the symbol that the market's prices are shown with.
+// The currency code is used for currencies without a known symbol.
+func (m *Market) CurrencySymbol() string {
+	if sym, ok := currencySymbols[m.Currency]; ok {
+		return sym
+	}
+	return m.Currency + " "
+}
+
+// FormatPrice formats an amount of money in the market's currency.
+func (m *Market) FormatPrice(amount float64) string {
+	return m.CurrencySymbol() + strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// DefaultLanguage returns the default language code of the market.
+func (m *Market) DefaultLanguage() string {
+	if len(m.Languages) == 0 {
+		return DefaultLang
+	}
+	return m.Languages[0]
+}
+
+// IsRegion tells whether or not a region code (like a state or province) can
+// be used in an address in the market.
+func (m *Market) IsRegion(code string) bool {
+	if !isStateCode(code) {
+		return false
+	}
+	if len(m.Regions) == 0 {
+		return true
+	}
+	for _, r := range m.Regions {
+		if r == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ParsePostalCode checks a postal code and returns it in the market's
+// standard format.
+func (m *Market) ParsePostalCode(code string) (string, bool) {
+	words := strings.Fields(code)
+	if len(words) == 0 {
+		return "", false
+	}
+	norm, n := m.postal(words)
+	if n != len(words) {
+		return "", false
+	}
+	return norm, true
+}
+
+// ParsePostalCode checks a postal code against all of the supported markets
+// and returns it in the standard format of the first market it belongs to.
+func ParsePostalCode(code string) (string, bool) {
+	for _, c := range MarketCodes() {
+		if norm, ok := markets[c].ParsePostalCode(code); ok {
+			return norm, true
+		}
+	}
+	return "", false
+}
+
+func usPostalCode(words []string) (string, int) {
+	if zip, ok := parseZip(words[len(words)-1]); ok {
+		return zip, 1
+	}
+	return "", 0
+}
+
+// caPostalCode parses a Canadian postal code which may or may not have a
+// space in the middle ("K1A 0B1" or "K1A0B1").
+func caPostalCode(words []string) (string, int) {
+	last := strings.ToUpper(words[len(words)-1])
+	if isCAPostalCode(last) {
+		return last[:3] + " " + last[3:], 1
+	}
+	if len(words) < 2 {
+		return "", 0
+	}
+	code := strings.ToUpper(words[len(words)-2]) + last
+	if len(last) == 3 && isCAPostalCode(code) {
+		return code[:3] + " " + code[3:], 2
+	}
+	return "", 0
+}
+
+func isCAPostalCode(s string) bool {
+	if len(s) != 6 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if i%2 == 0 && (s[i] < 'A' || s[i] > 'Z') {
+			return false
+		}
+		if i%2 == 1 && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package dawg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMarket_ParseAddress(t *testing.T) {
+	addr, err := MarketCA.ParseAddress("100 Queen St W, Toronto, ON M5H 2N2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.StreetNum != "100" || addr.StreetName != "Queen St W" || addr.CityName != "Toronto" {
+		t.Errorf("wrong street or city: %+v", addr)
+	}
+	if addr.State != "ON" || addr.Zipcode != "M5H 2N2" {
+		t.Errorf("wrong province or postal code: %s %s", addr.State, addr.Zipcode)
+	}
+	addr, err = MarketCA.ParseAddress("55 Sparks St Unit 4 Ottawa on k1p5a5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr.Zipcode != "K1P 5A5" || addr.CityName != "Ottawa" || addr.UnitNumber != "4" {
+		t.Errorf("wrong address: %+v", addr)
+	}
+
+	for _, tc := range []struct {
+		raw, part string
+	}{
+		{"100 Queen St W, Toronto, ON 90210", "postal code"},
+		{"100 Queen St W, Toronto, XX M5H 2N2", "province"},
+		{"100 Queen St W, Toronto, ON M5H 222", "postal code"},
+	} {
+		_, err = MarketCA.ParseAddress(tc.raw)
+		var e *AddressError
+		if !errors.As(err, &e) || e.Part != tc.part {
+			t.Errorf("%q: expected a %s error, got %v", tc.raw, tc.part, err)
+		}
+	}
+	if _, err = MarketUS.ParseAddress("100 Queen St W, Toronto, ON M5H 2N2"); err == nil {
+		t.Error("a canadian address should not parse in the us market")
+	}
+}
+
+func TestMarkets(t *testing.T) {
+	m, err := LookupMarket("CA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m != MarketCA {
+		t.Error("wrong market")
+	}
+	if m, _ = LookupMarket(""); m != MarketUS {
+		t.Error("the us should be the default market")
+	}
+	if _, err = LookupMarket("xx"); err == nil {
+		t.Error("expected an error for an unknown market")
+	}
+	for raw, exp := range map[string]string{
+		"20500":     "20500",
+		"205001234": "20500-1234",
+		"k1a 0b1":   "K1A 0B1",
+		"K1A0B1":    "K1A 0B1",
+		"K1A 0B1 2": "",
+		"2050":      "",
+		"K1A-0B1":   "",
+	} {
+		if code, _ := ParsePostalCode(raw); code != exp {
+			t.Errorf("ParsePostalCode(%q): expected %q, got %q", raw, exp, code)
+		}
+	}
+
+	c, err := NewClient(WithMarket(MarketCA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cli.host != "order.dominos.ca" || c.cli.language() != "en" || c.Market() != MarketCA {
+		t.Error("client should use the canadian market")
+	}
+	c, err = NewClient(WithLanguage("fr"), WithBaseURL("http://localhost:8080"), WithMarket(MarketCA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cli.language() != "fr" {
+		t.Error("the language should be able to change within a market")
+	}
+	if c.cli.host != "localhost:8080" {
+		t.Errorf("the base url should not be overwritten by the market, got %q", c.cli.host)
+	}
+	if _, err = c.ParseAddress("1 Rue Main, Montreal, QC H2X 1Y4"); err != nil {
+		t.Error(err)
+	}
+
+	defer SetMarket(MarketUS)
+	SetMarket(MarketCA)
+	if DefaultMarket() != MarketCA || orderClient.host != "order.dominos.ca" {
+		t.Error("the default market was not set")
+	}
+	if _, err = ParseAddress("100 Queen St W, Toronto, ON M5H 2N2"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMarket_FormatPrice(t *testing.T) {
+	if p := MarketUS.FormatPrice(12.5); p != "$12.50" {
+		t.Errorf("wrong price: %q", p)
+	}
+	if p := MarketCA.FormatPrice(3); p != "$3.00" {
+		t.Errorf("wrong price: %q", p)
+	}
+	m := &Market{Currency: "EUR"}
+	if p := m.FormatPrice(1.239); p != "EUR 1.24" {
+		t.Errorf("wrong price for a currency with no symbol: %q", p)
+	}
+}
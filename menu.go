@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+	"sort"
 	"strings"
 	"unicode/utf8"
 
@@ -47,9 +48,12 @@ type menuCmd struct {
 	verbose        bool
 	toppings       bool
 	preconfigured  bool
+	coupons        bool
 	showCategories bool
 	item           string
 	category       string
+	search         string
+	changes        bool
 }
 
 func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
@@ -73,6 +77,14 @@ func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if c.search != "" {
+		return c.printSearch(c.Output(), c.search)
+	}
+
+	if c.changes {
+		return c.printMenuChanges(c.Output())
+	}
+
 	if c.item != "" {
 		prod := c.Menu().FindItem(c.item)
 		if prod == nil {
@@ -86,6 +98,11 @@ func (c *menuCmd) Run(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	if c.coupons {
+		c.printCoupons()
+		return nil
+	}
+
 	// printmenu and pageMenu handle most of the menu command's flags
 	if c.page {
 		return c.pageMenu(strings.ToLower(c.category))
@@ -116,7 +133,13 @@ func NewMenuCmd(b cli.Builder) cli.CliCommand {
 
 To show a subdivision of the menu, give an item or
 category to the --category and --item flags or give them
-as an argument to the command itself.`
+as an argument to the command itself.
+
+To find an item without knowing its code, use --search. The best matches
+for the search are shown with their codes and prices, small typos are ok.
+
+The menu is refreshed every so often and any changes to it are recorded,
+use --changes to see what changed the last time it was refreshed.`
 
 	c.Cmd().ValidArgsFunction = c.categoryCompletion
 
@@ -127,10 +150,13 @@ as an argument to the command itself.`
 
 	flags.StringVarP(&c.item, "item", "i", "", "show info on the menu item given")
 	flags.StringVarP(&c.category, "category", "c", "", "show one category on the menu")
+	flags.StringVarP(&c.search, "search", "s", "", "search the menu and show the best matches")
+	flags.BoolVar(&c.changes, "changes", false, "show what changed on the menu since the last refresh")
 
 	flags.BoolVarP(&c.toppings, "toppings", "t", c.toppings, "print out the toppings on the menu")
 	flags.BoolVarP(&c.preconfigured, "preconfigured",
 		"p", c.preconfigured, "show the pre-configured products on the dominos menu")
+	flags.BoolVar(&c.coupons, "coupons", c.coupons, "print out the coupons available at the store")
 	flags.BoolVar(&c.showCategories, "show-categories", c.showCategories, "print categories")
 	return c
 }
@@ -181,6 +207,114 @@ func (c *menuCmd) printToppings() {
 	}
 }
 
+func (c *menuCmd) printCoupons() {
+	var (
+		menu     = c.Menu()
+		codes    = make([]string, 0, len(menu.Coupons))
+		currency = dawg.DefaultMarket().CurrencySymbol()
+	)
+	for code := range menu.Coupons {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	for _, code := range codes {
+		coupon := menu.Coupons[code]
+		price := spaces(strLen(currency) + 5)
+		if coupon.Price != "" {
+			price = fmt.Sprintf("%s%-5s", currency, coupon.Price)
+		}
+		fmt.Fprintln(c.Output(), " ", code, spaces(6-strLen(code)), price, coupon.Name)
+	}
+}
+
+// maxSearchResults is the most results that menu --search will show.
+const maxSearchResults = 10
+
+func (c *menuCmd) printSearch(w io.Writer, query string) error {
+	results := c.Menu().Search(query)
+	if len(results) == 0 {
+		return fmt.Errorf("nothing on the menu matches '%s'", query)
+	}
+	if len(results) > maxSearchResults {
+		results = results[:maxSearchResults]
+	}
+	currency := dawg.DefaultMarket().CurrencySymbol()
+	width := 0
+	for _, r := range results {
+		if n := strLen(r.Item.ItemCode()); n > width {
+			width = n
+		}
+	}
+	for _, r := range results {
+		code := r.Item.ItemCode()
+		fmt.Fprintln(w, " ", code, spaces(width-strLen(code)), priceColumn(currency, r.Price), r.Item.ItemName())
+	}
+	return nil
+}
+
+// priceColumn pads a price so that a list of prices lines up, a missing
+// price is left blank.
+func priceColumn(currency, price string) string {
+	if price == "" {
+		return spaces(strLen(currency) + 5)
+	}
+	return fmt.Sprintf("%s%-5s", currency, price)
+}
+
+func (c *menuCmd) printMenuChanges(w io.Writer) error {
+	updates, err := data.GetMenuUpdates(c.db)
+	if err != nil {
+		return err
+	}
+	if len(updates) == 0 {
+		fmt.Fprintln(w, "no menu changes have been recorded")
+		return nil
+	}
+	u := updates[len(updates)-1]
+	fmt.Fprintf(w, "Menu changes for store %s on %s:\n", u.StoreID, u.Time.Format("Jan 2 3:04 PM"))
+
+	printCodes := func(title string, codes []string) {
+		if len(codes) > 0 {
+			fmt.Fprintf(w, "  %s: %s\n", title, strings.Join(codes, ", "))
+		}
+	}
+	printCodes("added products", u.AddedProducts)
+	printCodes("removed products", u.RemovedProducts)
+	printCodes("added variants", u.AddedVariants)
+	printCodes("removed variants", u.RemovedVariants)
+	if len(u.PriceChanges) > 0 {
+		fmt.Fprintln(w, "  price changes:")
+		currency := dawg.DefaultMarket().CurrencySymbol()
+		width := 0
+		for _, p := range u.PriceChanges {
+			if n := strLen(p.Code); n > width {
+				width = n
+			}
+		}
+		for _, p := range u.PriceChanges {
+			fmt.Fprintf(w, "    %s%s %s%s -> %s%s\n", p.Code, spaces(width-strLen(p.Code)), currency, p.Old, currency, p.New)
+		}
+	}
+	for _, cat := range sortedToppingCategories(u.AddedToppings) {
+		printCodes("added "+strings.ToLower(cat)+" toppings", u.AddedToppings[cat])
+	}
+	for _, cat := range sortedToppingCategories(u.RemovedToppings) {
+		printCodes("removed "+strings.ToLower(cat)+" toppings", u.RemovedToppings[cat])
+	}
+	printCodes("products with new toppings", u.ToppingsChanged)
+	return nil
+}
+
+func sortedToppingCategories(toppings map[string][]string) []string {
+	cats := make([]string, 0, len(toppings))
+	for cat := range toppings {
+		cats = append(cats, cat)
+	}
+	sort.Strings(cats)
+	return cats
+}
+
 func (c *menuCmd) pageMenu(category string) error {
 	less := exec.Command("less")
 	less.Stdout = c.Output()
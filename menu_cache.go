@@ -4,8 +4,12 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"fmt"
 	"io"
 	"log"
+	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/harrybrwn/apizza/dawg"
@@ -54,6 +58,10 @@ type generalMenuCacher struct {
 
 	newEncoder func(io.Writer) Encoder
 	newDecoder func(io.Reader) Decoder
+
+	// warn is where warnings about saved orders with items that were
+	// removed from the menu are written.
+	warn io.Writer
 }
 
 // NewJSONMenuCacher will create a new MenuCacher that stores the
@@ -69,6 +77,7 @@ func NewJSONMenuCacher(
 		getstore:   store,
 		newEncoder: func(w io.Writer) Encoder { return json.NewEncoder(w) },
 		newDecoder: func(r io.Reader) Decoder { return json.NewDecoder(r) },
+		warn:       os.Stderr,
 	}
 	mc.Updater = cache.NewUpdater(decay, mc.cacheNewMenu, mc.getCachedMenu)
 	return mc
@@ -87,6 +96,7 @@ func NewGobMenuCacher(
 		getstore:   store,
 		newEncoder: func(w io.Writer) Encoder { return gob.NewEncoder(w) },
 		newDecoder: func(r io.Reader) Decoder { return gob.NewDecoder(r) },
+		warn:       os.Stderr,
 	}
 	mc.Updater = cache.NewUpdater(decay, mc.cacheNewMenu, mc.getCachedMenu)
 	return mc
@@ -101,14 +111,71 @@ func (mc *generalMenuCacher) Menu() *dawg.Menu {
 
 func (mc *generalMenuCacher) cacheNewMenu() error {
 	var e1, e2 error
+	old := mc.cachedMenu()
 	mc.m, e1 = mc.getstore().Menu()
 	log.Println("caching another menu")
+	if e1 == nil && old != nil && old.ID == mc.m.ID {
+		e1 = mc.recordChanges(old, mc.m)
+	}
 
 	buf := &bytes.Buffer{}
 	e2 = mc.newEncoder(buf).Encode(mc.m)
 	return errs.Append(e1, e2, mc.db.Put("menu", buf.Bytes()))
 }
 
+// cachedMenu decodes the menu that is in the database, it returns nil if
+// there is no menu or it cannot be decoded.
+func (mc *generalMenuCacher) cachedMenu() *dawg.Menu {
+	raw, err := mc.db.Get("menu")
+	if raw == nil || err != nil {
+		return nil
+	}
+	m := new(dawg.Menu)
+	if err = mc.newDecoder(bytes.NewBuffer(raw)).Decode(m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// recordChanges saves the differences between the old and new menus to the
+// menu change log and warns about saved orders that use items which are no
+// longer on the menu.
+func (mc *generalMenuCacher) recordChanges(old, new *dawg.Menu) error {
+	changes := dawg.CompareMenus(old, new)
+	if changes.Empty() {
+		return nil
+	}
+	log.Printf("recording menu changes for store %s", new.ID)
+	err := saveMenuUpdate(mc.db, &MenuUpdate{
+		Time:        time.Now(),
+		StoreID:     new.ID,
+		MenuChanges: *changes,
+	})
+	if err != nil {
+		return err
+	}
+
+	db, ok := mc.db.(cache.MapDB)
+	if !ok {
+		log.Printf("cannot check saved orders for removed items: %T cannot list its keys", mc.db)
+		return nil
+	}
+	orders, err := OrdersWithRemovedItems(db, changes)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(orders))
+	for name := range orders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(mc.warn, "warning: the order '%s' has items that are no longer on the menu: %s\n",
+			name, strings.Join(orders[name], ", "))
+	}
+	return nil
+}
+
 func (mc *generalMenuCacher) getCachedMenu() error {
 	if mc.m == nil {
 		mc.m = new(dawg.Menu)
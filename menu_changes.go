@@ -0,0 +1,96 @@
+package data
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/errs"
+)
+
+const (
+	// MenuChangesKey is the database key for the log of menu changes.
+	MenuChangesKey = "menu_changes"
+
+	// maxMenuUpdates is the number of menu updates kept in the change log.
+	maxMenuUpdates = 10
+)
+
+// MenuUpdate is a record of the changes found when a new menu was cached.
+type MenuUpdate struct {
+	Time    time.Time `json:"time"`
+	StoreID string    `json:"store_id"`
+	dawg.MenuChanges
+}
+
+// GetMenuUpdates gets the log of menu changes, the most recent update is
+// last. The log is empty if no changes have been recorded.
+func GetMenuUpdates(db cache.Getter) ([]*MenuUpdate, error) {
+	raw, err := db.Get(MenuChangesKey)
+	if raw == nil {
+		return nil, err
+	}
+	var updates []*MenuUpdate
+	return updates, errs.Pair(err, json.Unmarshal(raw, &updates))
+}
+
+// saveMenuUpdate adds an update to the end of the change log and removes
+// the oldest updates if there are too many.
+func saveMenuUpdate(db cache.Storage, u *MenuUpdate) error {
+	updates, err := GetMenuUpdates(db)
+	if err != nil {
+		return err
+	}
+	updates = append(updates, u)
+	if len(updates) > maxMenuUpdates {
+		updates = updates[len(updates)-maxMenuUpdates:]
+	}
+	raw, err := json.Marshal(updates)
+	if err != nil {
+		return err
+	}
+	return db.Put(MenuChangesKey, raw)
+}
+
+// OrdersWithRemovedItems finds the saved orders that have a product or
+// topping that was removed from the menu. The result maps the order names
+// to the codes that are no longer on the menu. Orders that cannot be decoded
+// are logged and skipped.
+func OrdersWithRemovedItems(db cache.MapDB, changes *dawg.MenuChanges) (map[string][]string, error) {
+	all, err := db.Map()
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string][]string)
+	for key, raw := range all {
+		if !strings.HasPrefix(key, OrderPrefix) {
+			continue
+		}
+		o := new(dawg.Order)
+		if err = json.Unmarshal(raw, o); err != nil {
+			log.Printf("could not check order '%s' for removed items: %v",
+				strings.TrimPrefix(key, OrderPrefix), err)
+			continue
+		}
+		var codes []string
+		for _, p := range o.Products {
+			if changes.Removed(p.Code) {
+				codes = append(codes, p.Code)
+			}
+			for top := range p.Opts {
+				if changes.ToppingRemoved(top) {
+					codes = append(codes, top)
+				}
+			}
+		}
+		if len(codes) > 0 {
+			sort.Strings(codes)
+			found[strings.TrimPrefix(key, OrderPrefix)] = codes
+		}
+	}
+	return found, nil
+}
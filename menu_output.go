@@ -47,6 +47,9 @@ func ItemInfo(prod dawg.Item, menu *dawg.Menu) error {
 	case *dawg.PreConfiguredProduct:
 		fmt.Fprintf(o, "  Description: '%s'\n", FormatLineIndent(p.Description, 70, 16))
 		fmt.Fprintf(o, "  Size: %s\n", p.Size)
+		if v := p.GetVariant(); v != nil {
+			fmt.Fprintf(o, "  Variant: '%s' [%s]\n", v.ItemName(), v.ItemCode())
+		}
 
 	case *dawg.Product:
 		PrintProduct(p)
@@ -68,6 +71,12 @@ func iteminfo(i dawg.Item, menu *dawg.Menu) {
 			fmt.Fprintf(output, "    %s:%s%s\n", tname, " ", param)
 		}
 	}
+	if si, ok := i.(dawg.SideItem); ok && len(si.Sides()) > 0 {
+		fmt.Fprintln(output, "  Sides:")
+		for name, qty := range dawg.ReadableSides(si, menu) {
+			fmt.Fprintf(output, "    %s: %s\n", name, qty)
+		}
+	}
 }
 
 func printCategory(code string, indent int, m *dawg.Menu) {
@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
 	"github.com/harrybrwn/apizza/pkg/tests"
 )
 
@@ -42,6 +44,67 @@ func TestFindProduct(t *testing.T) {
 	}
 }
 
+func TestMenuSearch(t *testing.T) {
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewMenuCmd(r).(*menuCmd)
+
+	if err := c.printSearch(c.Output(), "cheese pizza"); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(r.Out.String(), "\n"); lines < 1 || lines > maxSearchResults {
+		t.Errorf("wrong number of search results: %d", lines)
+	}
+	if !strings.Contains(r.Out.String(), "$") {
+		t.Error("search results should have prices")
+	}
+	if err := c.printSearch(c.Output(), "xyzzy"); err == nil {
+		t.Error("expected an error when nothing matches")
+	}
+}
+
+func TestPriceColumn(t *testing.T) {
+	for _, tc := range []struct{ currency, price, exp string }{
+		{"$", "5.99", "$5.99 "},
+		{"$", "12.99", "$12.99"},
+		{"$", "", "      "},
+		{"CAD ", "5.99", "CAD 5.99 "},
+	} {
+		if got := priceColumn(tc.currency, tc.price); got != tc.exp {
+			t.Errorf("priceColumn(%q, %q): got %q, want %q", tc.currency, tc.price, got, tc.exp)
+		}
+	}
+}
+
+func TestMenuChanges(t *testing.T) {
+	tests.InitHelpers(t)
+	r := cmdtest.NewRecorder()
+	defer r.CleanUp()
+	c := NewMenuCmd(r).(*menuCmd)
+
+	tests.Check(c.printMenuChanges(c.Output()))
+	tests.Compare(t, r.Out.String(), "no menu changes have been recorded\n")
+	r.ClearBuf()
+
+	tests.Check(r.DB().Put(data.MenuChangesKey, []byte(`[{
+		"time": "2020-03-01T12:30:00Z", "store_id": "4336",
+		"RemovedVariants": ["10SCREEN"],
+		"PriceChanges": [
+			{"Code": "12SCREEN", "Old": "11.99", "New": "12.99"},
+			{"Code": "B8PCPT", "Old": "8.99", "New": "9.49"}
+		],
+		"RemovedToppings": {"Pizza": ["P"]}
+	}]`)))
+	tests.Check(c.printMenuChanges(c.Output()))
+	tests.Compare(t, r.Out.String(), `Menu changes for store 4336 on Mar 1 12:30 PM:
+  removed variants: 10SCREEN
+  price changes:
+    12SCREEN $11.99 -> $12.99
+    B8PCPT   $8.99 -> $9.49
+  removed pizza toppings: P
+`)
+}
+
 func TestStringStuff(t *testing.T) {
 	if strLen("123456") != 6 {
 		t.Error("wrong string len")
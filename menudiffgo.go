@@ -0,0 +1,155 @@
+package dawg
+
+import "sort"
+
+// MenuChanges are the differences between an old and a new version of a
+// store's menu, see CompareMenus.
+type MenuChanges struct {
+	// AddedProducts and RemovedProducts include pre-configured products.
+	AddedProducts   []string `json:",omitempty"`
+	RemovedProducts []string `json:",omitempty"`
+	AddedVariants   []string `json:",omitempty"`
+	RemovedVariants []string `json:",omitempty"`
+
+	// PriceChanges are the variants that have a new price.
+	PriceChanges []PriceChange `json:",omitempty"`
+
+	// AddedToppings and RemovedToppings are the topping codes that were
+	// added to or removed from the menu, grouped by topping category.
+	AddedToppings   map[string][]string `json:",omitempty"`
+	RemovedToppings map[string][]string `json:",omitempty"`
+	// ToppingsChanged are the products that have different available
+	// toppings.
+	ToppingsChanged []string `json:",omitempty"`
+}
+
+// PriceChange is a change in the price of a variant.
+type PriceChange struct {
+	Code     string
+	Old, New string
+}
+
+// CompareMenus finds what changed between two versions of a menu. All of the
+// lists of codes are sorted.
+func CompareMenus(old, new *Menu) *MenuChanges {
+	c := &MenuChanges{}
+	c.AddedProducts, c.RemovedProducts = compareCodes(productCodes(old), productCodes(new))
+	c.AddedVariants, c.RemovedVariants = compareCodes(variantCodes(old), variantCodes(new))
+
+	for code, v := range new.Variants {
+		if prev, ok := old.Variants[code]; ok && prev.Price != v.Price {
+			c.PriceChanges = append(c.PriceChanges, PriceChange{Code: code, Old: prev.Price, New: v.Price})
+		}
+	}
+	sort.Slice(c.PriceChanges, func(i, j int) bool {
+		return c.PriceChanges[i].Code < c.PriceChanges[j].Code
+	})
+
+	categories := make(map[string]bool)
+	for category := range old.Toppings {
+		categories[category] = true
+	}
+	for category := range new.Toppings {
+		categories[category] = true
+	}
+	for category := range categories {
+		added, removed := compareCodes(toppingCodes(old.Toppings[category]), toppingCodes(new.Toppings[category]))
+		if len(added) > 0 {
+			if c.AddedToppings == nil {
+				c.AddedToppings = make(map[string][]string)
+			}
+			c.AddedToppings[category] = added
+		}
+		if len(removed) > 0 {
+			if c.RemovedToppings == nil {
+				c.RemovedToppings = make(map[string][]string)
+			}
+			c.RemovedToppings[category] = removed
+		}
+	}
+	for code, p := range new.Products {
+		if prev, ok := old.Products[code]; ok && prev.AvailableToppings != p.AvailableToppings {
+			c.ToppingsChanged = append(c.ToppingsChanged, code)
+		}
+	}
+	sort.Strings(c.ToppingsChanged)
+	return c
+}
+
+// Empty returns true if nothing changed.
+func (c *MenuChanges) Empty() bool {
+	return len(c.AddedProducts) == 0 && len(c.RemovedProducts) == 0 &&
+		len(c.AddedVariants) == 0 && len(c.RemovedVariants) == 0 &&
+		len(c.PriceChanges) == 0 && len(c.AddedToppings) == 0 &&
+		len(c.RemovedToppings) == 0 && len(c.ToppingsChanged) == 0
+}
+
+// Removed returns true if a product, pre-configured product, or variant
+// code was removed from the menu.
+func (c *MenuChanges) Removed(code string) bool {
+	for _, list := range [][]string{c.RemovedProducts, c.RemovedVariants} {
+		i := sort.SearchStrings(list, code)
+		if i < len(list) && list[i] == code {
+			return true
+		}
+	}
+	return false
+}
+
+// ToppingRemoved returns true if a topping code was removed from any of the
+// menu's topping categories.
+func (c *MenuChanges) ToppingRemoved(code string) bool {
+	for _, list := range c.RemovedToppings {
+		for _, top := range list {
+			if top == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compareCodes returns the sorted codes that are only in b (added) and only
+// in a (removed).
+func compareCodes(a, b map[string]bool) (added, removed []string) {
+	for code := range b {
+		if !a[code] {
+			added = append(added, code)
+		}
+	}
+	for code := range a {
+		if !b[code] {
+			removed = append(removed, code)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
+
+func productCodes(m *Menu) map[string]bool {
+	codes := make(map[string]bool, len(m.Products)+len(m.Preconfigured))
+	for code := range m.Products {
+		codes[code] = true
+	}
+	for code := range m.Preconfigured {
+		codes[code] = true
+	}
+	return codes
+}
+
+func variantCodes(m *Menu) map[string]bool {
+	codes := make(map[string]bool, len(m.Variants))
+	for code := range m.Variants {
+		codes[code] = true
+	}
+	return codes
+}
+
+func toppingCodes(toppings map[string]Topping) map[string]bool {
+	codes := make(map[string]bool, len(toppings))
+	for code := range toppings {
+		codes[code] = true
+	}
+	return codes
+}
@@ -0,0 +1,49 @@
+package dawg
+
+import "testing"
+
+func TestCompareMenus(t *testing.T) {
+	old, new := menuFromFile(t), menuFromFile(t)
+	if c := CompareMenus(old, new); !c.Empty() {
+		t.Errorf("the same menu should have no changes: %+v", c)
+	}
+
+	delete(new.Products, "F_GARLICT")
+	delete(new.Variants, "B8PCGT")
+	delete(new.Preconfigured, "B32PBIT")
+	new.Variants["NEWCODE"] = &Variant{ItemCommon: ItemCommon{Code: "NEWCODE"}}
+	new.Variants["14SCREEN"] = &Variant{ItemCommon: ItemCommon{Code: "14SCREEN"}, Price: "99.99"}
+	delete(new.Toppings["Pizza"], "K")
+	pizza := *new.Products["S_PIZZA"]
+	pizza.AvailableToppings = "X,C"
+	new.Products["S_PIZZA"] = &pizza
+
+	c := CompareMenus(old, new)
+	if c.Empty() {
+		t.Fatal("expected changes")
+	}
+	if len(c.RemovedProducts) != 2 || c.RemovedProducts[0] != "B32PBIT" || c.RemovedProducts[1] != "F_GARLICT" {
+		t.Errorf("wrong removed products: %v", c.RemovedProducts)
+	}
+	if len(c.RemovedVariants) != 1 || c.RemovedVariants[0] != "B8PCGT" {
+		t.Errorf("wrong removed variants: %v", c.RemovedVariants)
+	}
+	if len(c.AddedVariants) != 1 || c.AddedVariants[0] != "NEWCODE" {
+		t.Errorf("wrong added variants: %v", c.AddedVariants)
+	}
+	if len(c.PriceChanges) != 1 || c.PriceChanges[0] != (PriceChange{"14SCREEN", "13.99", "99.99"}) {
+		t.Errorf("wrong price changes: %v", c.PriceChanges)
+	}
+	if r := c.RemovedToppings["Pizza"]; len(r) != 1 || r[0] != "K" {
+		t.Errorf("wrong removed toppings: %v", c.RemovedToppings)
+	}
+	if len(c.ToppingsChanged) != 1 || c.ToppingsChanged[0] != "S_PIZZA" {
+		t.Errorf("wrong products with changed toppings: %v", c.ToppingsChanged)
+	}
+	if !c.Removed("B8PCGT") || !c.Removed("F_GARLICT") || c.Removed("14SCREEN") {
+		t.Error("Removed gave the wrong result")
+	}
+	if !c.ToppingRemoved("K") || c.ToppingRemoved("X") {
+		t.Error("ToppingRemoved gave the wrong result")
+	}
+}
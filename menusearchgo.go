@@ -0,0 +1,231 @@
+package dawg
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// SearchResult is an item found with Menu.Search.
+type SearchResult struct {
+	Item Item
+	// Price is the price of the item. Products do not have a price so the
+	// lowest price of the product's variants is used.
+	Price string
+	// Score is how well the item matched the search, higher is better.
+	Score float64
+}
+
+// How much a match in each part of an item is worth.
+const (
+	nameWeight = 3.0
+	tagWeight  = 1.5
+	descWeight = 1.0
+	// phraseBonus is added when the whole search is found in an item's name.
+	phraseBonus = 2.0
+)
+
+// Search finds the products, variants, and pre-configured products that match
+// a query. The query is split into words that are compared with the words of
+// each item's name, description, and tags. Case is ignored, words can match the
+// beginning of a longer word, and small typos are allowed. Every word in the
+// query must match for an item to be found.
+//
+// The results are sorted so that the best matches are first.
+func (m *Menu) Search(query string) []SearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+	phrase := strings.Join(terms, " ")
+	results := make([]SearchResult, 0)
+	add := func(item Item, price, desc string, tags map[string]interface{}) {
+		name := strings.Join(searchWords(item.ItemName()), " ")
+		score := scoreItem(terms, searchWords(name), searchWords(desc), tagWords(tags))
+		if score == 0 {
+			return
+		}
+		if strings.Contains(name, phrase) {
+			score += phraseBonus
+		}
+		results = append(results, SearchResult{Item: item, Price: price, Score: score})
+	}
+
+	for _, p := range m.Products {
+		add(p, m.lowestPrice(p), p.Description, p.Tags)
+	}
+	for _, pc := range m.Preconfigured {
+		pc = m.initPreconfigured(pc)
+		var price string
+		if v := pc.GetVariant(); v != nil {
+			price = v.Price
+		}
+		add(pc, price, pc.Description, pc.Tags)
+	}
+	for _, v := range m.Variants {
+		add(m.initVariant(v), v.Price, "", v.Tags)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].Item.ItemCode() < results[j].Item.ItemCode()
+	})
+	return results
+}
+
+// scoreItem gives the score of an item using the best match of each term.
+// Zero is returned if any of the terms do not match.
+func scoreItem(terms, name, desc, tags []string) float64 {
+	var total float64
+	for _, term := range terms {
+		best := nameWeight * matchWords(term, name)
+		if s := tagWeight * matchWords(term, tags); s > best {
+			best = s
+		}
+		if s := descWeight * matchWords(term, desc); s > best {
+			best = s
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// matchWords returns how well a search term matches the closest word in a
+// list of words. An exact match is 1, matching the start of a word is worth
+// a bit less, and a word with a typo is worth the least.
+func matchWords(term string, words []string) float64 {
+	var best float64
+	for _, w := range words {
+		var s float64
+		switch {
+		case w == term:
+			return 1
+		case len(term) >= 3 && strings.HasPrefix(w, term):
+			s = 0.8
+		case editDistance(term, w) <= typosAllowed(term):
+			s = 0.6
+		}
+		if s > best {
+			best = s
+		}
+	}
+	return best
+}
+
+// typosAllowed is the number of typos that a search term can have, longer
+// words can have more.
+func typosAllowed(term string) int {
+	switch n := len(term); {
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// editDistance is the Levenshtein distance between two words.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min3(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// searchStopWords are left out of search queries.
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "the": true, "of": true,
+	"with": true, "in": true, "on": true, "or": true,
+}
+
+func searchTerms(query string) []string {
+	words := searchWords(query)
+	terms := words[:0]
+	for _, w := range words {
+		if !searchStopWords[w] {
+			terms = append(terms, w)
+		}
+	}
+	return terms
+}
+
+// searchWords splits text into lower case words.
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tagWords gets the searchable words from an item's tags. Tags with text are
+// searched by their value and tags that are true are searched by name. The
+// default toppings and sides are codes so they are left out.
+func tagWords(tags map[string]interface{}) []string {
+	var words []string
+	for key, val := range tags {
+		if strings.HasPrefix(key, "Default") {
+			continue
+		}
+		switch v := val.(type) {
+		case string:
+			words = append(words, searchWords(v)...)
+		case bool:
+			if v {
+				words = append(words, searchWords(key)...)
+			}
+		}
+	}
+	return words
+}
+
+// lowestPrice finds the lowest price of a product's variants.
+func (m *Menu) lowestPrice(p *Product) string {
+	var (
+		lowest string
+		low    float64
+	)
+	for _, code := range p.Variants {
+		v, ok := m.Variants[code]
+		if !ok {
+			continue
+		}
+		price, err := strconv.ParseFloat(v.Price, 64)
+		if err != nil {
+			continue
+		}
+		if lowest == "" || price < low {
+			lowest, low = v.Price, price
+		}
+	}
+	return lowest
+}
@@ -0,0 +1,49 @@
+package dawg
+
+import "testing"
+
+func TestMenu_Search(t *testing.T) {
+	menu := menuFromFile(t)
+	for _, query := range []string{"garlic bread", "GARLIC BREAD twists", "garlik bred", "the garlic bread"} {
+		results := menu.Search(query)
+		if len(results) < 2 {
+			t.Fatalf("%q: expected at least 2 results, got %d", query, len(results))
+		}
+		for _, r := range results[:2] {
+			if r.Item.ItemName() != "Garlic Bread Twists" {
+				t.Errorf("%q: wrong result %s (%s)", query, r.Item.ItemCode(), r.Item.ItemName())
+			}
+		}
+		if results[0].Score < results[len(results)-1].Score {
+			t.Errorf("%q: results are not sorted", query)
+		}
+	}
+
+	results := menu.Search("garlic bread")
+	for _, r := range results[:2] {
+		if r.Price != "6.99" {
+			t.Errorf("wrong price for %s: %q", r.Item.ItemCode(), r.Price)
+		}
+	}
+	if results := menu.Search("parm bites"); len(results) == 0 {
+		t.Error("expected the start of a word to match")
+	} else if code := results[0].Item.ItemCode(); code != "B32PBIT" && code != "F_PBITES" {
+		t.Errorf("wrong first result for 'parm bites': %s", code)
+	}
+	if results := menu.Search("vegetarian"); len(results) == 0 {
+		t.Error("expected tags to be searched")
+	}
+	if results := menu.Search("xyzzy"); len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+	if results := menu.Search("  the "); results != nil {
+		t.Error("an empty query should have no results")
+	}
+
+	if d := editDistance("garlik", "garlic"); d != 1 {
+		t.Errorf("wrong edit distance: %d", d)
+	}
+	if d := editDistance("", "abc"); d != 3 {
+		t.Errorf("wrong edit distance: %d", d)
+	}
+}
@@ -1,9 +1,11 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 )
 
@@ -41,11 +43,23 @@ type Menu struct {
 	Variants      map[string]*Variant
 	Toppings      map[string]map[string]Topping
 	Preconfigured map[string]*PreConfiguredProduct `json:"PreconfiguredProducts"`
+	Coupons       map[string]*Coupon
 	Sides         map[string]map[string]struct {
 		ItemCommon
 		Description string
 	}
 
+	// Sizes and Flavors are the sizes and flavors (like pizza crusts) that
+	// variants can have, grouped by product type. See FindVariant.
+	Sizes   map[string]map[string]VariantOption
+	Flavors map[string]map[string]VariantOption
+	// ShortDescriptions are short descriptions of some variants and
+	// products, keyed by code.
+	ShortDescriptions map[string]struct {
+		Code        string
+		Description string
+	} `json:"ShortProductDescriptions"`
+
 	cli *client
 }
 
@@ -86,6 +100,15 @@ func (m *Menu) GetVariant(code string) (*Variant, error) {
 	return nil, fmt.Errorf("could not find variant '%s'", code)
 }
 
+// GetPreconfigured will get a pre-configured product from the menu that has
+// been initialized with its variant.
+func (m *Menu) GetPreconfigured(code string) (*PreConfiguredProduct, error) {
+	if pc, ok := m.Preconfigured[code]; ok {
+		return m.initPreconfigured(pc), nil
+	}
+	return nil, fmt.Errorf("could not find pre-configured product '%s'", code)
+}
+
 // FindItem looks in all the different menu categories for an item code given
 // as an argument.
 func (m *Menu) FindItem(code string) (itm Item) {
@@ -95,7 +118,7 @@ func (m *Menu) FindItem(code string) (itm Item) {
 	if i, ok = m.Products[code]; ok {
 		return i.(*Product)
 	} else if i, ok = m.Preconfigured[code]; ok {
-		return i.(*PreConfiguredProduct)
+		return m.initPreconfigured(i.(*PreConfiguredProduct))
 	} else if i, ok = m.Variants[code]; ok {
 		return m.initVariant(i.(*Variant))
 	}
@@ -162,11 +185,21 @@ func ReadableToppings(item Item, m *Menu) map[string]string {
 	)
 
 	t := item.Category()
+	if t == "" {
+		// order products that have been saved do not remember their category
+		if prod := m.productOf(item); prod != nil {
+			t = prod.ProductType
+		}
+	}
 	toppingSet = m.Toppings[t]
 
 	var key string
 	for topping, options := range item.Options() {
-		key = fmt.Sprintf("%s (%s)", toppingSet[topping].Name, topping)
+		if name := toppingSet[topping].Name; name != "" {
+			key = fmt.Sprintf("%s (%s)", name, topping)
+		} else {
+			key = topping
+		}
 		out[key] = translateOpt(options)
 	}
 	return out
@@ -197,36 +230,33 @@ func translateOpt(opt interface{}) string {
 	return param
 }
 
-func makeTopping(cover, amount string, optionQtys []string) map[string]string {
-	var key string
-
+func makeTopping(cover, amount string, optionQtys []string) (map[string]string, error) {
 	if !(strings.HasSuffix(amount, ".0") || strings.HasSuffix(amount, ".5")) {
 		amount += ".0"
 	}
-	if optionQtys != nil {
-		if !validateQtys(amount, optionQtys) {
-			// TODO: make this return a helpful error message
-			return nil
-		}
+	if _, err := strconv.ParseFloat(amount, 64); err != nil {
+		return nil, fmt.Errorf("invalid topping amount %q", amount)
+	}
+	if optionQtys != nil && !validateQtys(amount, optionQtys) {
+		return nil, fmt.Errorf("amount %s is not one of %s", amount, strings.Join(optionQtys, ", "))
 	}
 
 	switch cover {
 	case ToppingFull, ToppingLeft, ToppingRight:
-		key = cover
 	default:
-		// TODO: have this return an error message saying that the topping coverage was invalid
-		return nil
+		return nil, fmt.Errorf("invalid topping coverage %q, should be %s, %s, or %s",
+			cover, ToppingFull, ToppingLeft, ToppingRight)
 	}
-
-	return map[string]string{key: amount}
+	return map[string]string{cover: amount}, nil
 }
 
 func validateQtys(amount string, qtys []string) bool {
+	amnt, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return false
+	}
 	for _, qty := range qtys {
-		if len(qty) == 1 {
-			qty += ".0"
-		}
-		if qty == amount {
+		if q, err := strconv.ParseFloat(qty, 64); err == nil && q == amnt {
 			return true
 		}
 	}
@@ -240,9 +270,14 @@ func (m *Menu) initVariant(v *Variant) *Variant {
 	return v
 }
 
-func newMenu(c *client, id string) (*Menu, error) {
+func (m *Menu) initPreconfigured(pc *PreConfiguredProduct) *PreConfiguredProduct {
+	pc.FindVariant(m)
+	return pc
+}
+
+func newMenu(ctx context.Context, c *client, id string) (*Menu, error) {
 	path := format("/power/store/%s/menu", id)
-	b, err := c.get(path, Params{"lang": DefaultLang, "structured": "true"})
+	b, err := c.get(ctx, path, Params{"lang": c.language(), "structured": "true"})
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +167,51 @@ func TestFindItem(t *testing.T) {
 	tests.Exp(err)
 }
 
+func TestPreConfiguredProduct(t *testing.T) {
+	tests.InitHelpers(t)
+	m := menuFromFile(t)
+	pc, err := m.GetPreconfigured("P_14SCREEN")
+	tests.Check(err)
+	tests.StrEq(pc.Category(), "Pizza", "wrong category for %s", pc.Code)
+	if v := pc.GetVariant(); v == nil || v.Code != "14SCREEN" {
+		t.Fatal("pre-configured product should have been initialized with its variant")
+	}
+	for _, top := range []string{"P", "X", "C"} {
+		if _, ok := pc.Options()[top]; !ok {
+			t.Errorf("%s should have the %s topping", pc.Code, top)
+		}
+	}
+	_, err = m.GetPreconfigured("nothere")
+	tests.Exp(err)
+
+	zz, ok := m.FindItem("14SCEXTRAV").(*PreConfiguredProduct)
+	if !ok {
+		t.Fatal("14SCEXTRAV should be a pre-configured product")
+	}
+	tests.Check(zz.AddTopping("O", ToppingFull, "0"))
+	tests.Check(zz.AddTopping("C", ToppingFull, "2"))
+	tests.Exp(zz.AddTopping("C", ToppingFull, "7"))
+	tests.Check(m.ValidateTopping(zz, "O", ToppingFull, "0"))
+
+	op := OrderProductFromItem(zz)
+	tests.StrEq(op.Code, "14SCEXTRAV", "wrong order product code")
+	tests.StrEq(op.Category(), "Pizza", "order product should have the pre-configured category")
+	if top := op.Options()["O"].(map[string]string); top[ToppingFull] != "0.0" {
+		t.Errorf("onions should have been removed: %v", top)
+	}
+
+	readable := ReadableToppings(pc, m)
+	if _, ok := readable["Pepperoni (P)"]; !ok {
+		t.Errorf("wrong readable toppings: %v", readable)
+	}
+	// order products that are loaded from disk do not have a category
+	saved := &OrderProduct{ItemCommon: ItemCommon{Code: "14SCREEN"}, Opts: pc.Options()}
+	readable = ReadableToppings(saved, m)
+	if _, ok := readable["Pepperoni (P)"]; !ok {
+		t.Errorf("wrong readable toppings: %v", readable)
+	}
+}
+
 func TestTranslateOpt(t *testing.T) {
 	tests.InitHelpers(t)
 	opts := map[string]interface{}{
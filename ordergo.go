@@ -2,9 +2,9 @@ package dawg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
 )
 
 // TODO: alphabetize the Order struct fields and add some more documentation
@@ -31,11 +31,21 @@ type Order struct {
 	Email         string                 `json:"Email"`
 	Phone         string
 	Payments      []*orderPayment `json:"Payments"`
+	Coupons       []*OrderCoupon  `json:"Coupons"`
+
+	// FutureOrderTime is the time that a scheduled order will be delivered
+	// or ready for pickup. It is in the store's time zone and uses the
+	// FutureTimeFormat layout. Orders without one are made right away, see
+	// SetFutureTime.
+	FutureOrderTime string `json:",omitempty"`
 
 	// OrderName is not a field that is sent to dominos, but is just a way for
 	// users to name a specific order.
 	OrderName string `json:"-"`
 	price     float64
+	breakdown *PriceBreakdown
+	pricedKey string
+	pulseID   string
 	cli       *client
 }
 
@@ -57,28 +67,124 @@ func (o *Order) init() {
 
 // PlaceOrder is the method that sends the final order to dominos
 func (o *Order) PlaceOrder() error {
-	if err := o.prepare(); err != nil {
+	return o.PlaceOrderContext(context.Background())
+}
+
+// PlaceOrderContext sends the final order to dominos and uses the context
+// for all of the requests made while placing the order.
+func (o *Order) PlaceOrderContext(ctx context.Context) error {
+	if o.cli == nil {
+		o.cli = orderClient
+	}
+	return placeOrder(ctx, o.cli, o)
+}
+
+func placeOrder(ctx context.Context, c *client, o *Order) error {
+	if err := prepareOrder(ctx, c, o); err != nil {
 		return err
 	}
-	return sendOrder("/power/place-order", *o)
+	b, err := c.post(ctx, "/power/place-order", nil, o.raw())
+	if err != nil {
+		return err
+	}
+	resp := &priceingData{}
+	if json.Unmarshal(b, resp) == nil {
+		if resp.Order.OrderID != "" {
+			o.OrderID = resp.Order.OrderID
+		}
+		o.pulseID = resp.Order.PulseOrderGUID
+		if resp.Order.FutureOrderTime != "" {
+			o.FutureOrderTime = resp.Order.FutureOrderTime
+		}
+	}
+	return dominosErr(b)
+}
+
+// TrackingID returns the id used to find the order in the order tracker.
+// It will be empty until the order has been placed.
+func (o *Order) TrackingID() string {
+	if o.pulseID != "" {
+		return o.pulseID
+	}
+	return o.OrderID
 }
 
 // Price method returns the total price of an order.
 func (o *Order) Price() (float64, error) {
-	if o.price == 0.0 {
-		if err := o.prepare(); err != nil {
+	return o.PriceContext(context.Background())
+}
+
+// PriceContext returns the total price of an order using a context
+// for the pricing request.
+func (o *Order) PriceContext(ctx context.Context) (float64, error) {
+	if o.priceStale() {
+		if err := o.prepare(ctx); err != nil {
 			return -1.0, err
 		}
 	}
 	return o.price, nil
 }
 
+// PriceBreakdown returns the itemized price of the order along with the
+// estimated wait time.
+func (o *Order) PriceBreakdown() (*PriceBreakdown, error) {
+	return o.PriceBreakdownContext(context.Background())
+}
+
+// PriceBreakdownContext is the same as PriceBreakdown but with a context.
+func (o *Order) PriceBreakdownContext(ctx context.Context) (*PriceBreakdown, error) {
+	if o.priceStale() {
+		if err := o.prepare(ctx); err != nil {
+			return nil, err
+		}
+	}
+	b := *o.breakdown
+	return &b, nil
+}
+
+// Discount returns the total amount taken off the order by coupons.
+func (o *Order) Discount() (float64, error) {
+	b, err := o.PriceBreakdown()
+	if err != nil {
+		return 0, err
+	}
+	return b.Amounts.Discount, nil
+}
+
+// priceStale returns true if the order has not been priced or if it has
+// changed since it was last priced.
+func (o *Order) priceStale() bool {
+	return o.price == 0.0 || o.breakdown == nil || o.pricedKey != o.pricingKey()
+}
+
+// invalidatePrice clears the cached price of the order.
+func (o *Order) invalidatePrice() {
+	o.price = 0
+	o.breakdown = nil
+}
+
+// pricingKey is a snapshot of all the fields that change the order's price.
+func (o *Order) pricingKey() string {
+	b, err := json.Marshal(struct {
+		Service  string
+		StoreID  string
+		Address  *StreetAddr
+		Products []*OrderProduct
+		Coupons  []*OrderCoupon
+	}{o.ServiceMethod, o.StoreID, o.Address, o.Products, o.Coupons})
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
 // AddProduct adds a product to the Order from a Product Object
 func (o *Order) AddProduct(item Item) error {
 	if item == nil {
 		return errors.New("cannot add a nil item")
 	}
 	o.Products = append(o.Products, OrderProductFromItem(item))
+	o.invalidatePrice()
 	return nil
 }
 
@@ -90,6 +196,7 @@ func (o *Order) AddProductQty(item Item, n int) error {
 	p := OrderProductFromItem(item)
 	p.Qty = n
 	o.Products = append(o.Products, p)
+	o.invalidatePrice()
 	return nil
 }
 
@@ -111,6 +218,7 @@ func (o *Order) RemoveProduct(code string) error {
 		return errors.New("product not in order")
 	}
 	o.Products = tempProds
+	o.invalidatePrice()
 	return nil
 }
 
@@ -141,23 +249,45 @@ func (o *Order) SetName(name string) {
 // Validate sends and order to the validation endpoint to be validated by
 // Dominos' servers.
 func (o *Order) Validate() error {
-	return ValidateOrder(o)
+	return ValidateOrderContext(context.Background(), o)
+}
+
+// ValidateContext is the same as Validate but with a context.
+func (o *Order) ValidateContext(ctx context.Context) error {
+	return ValidateOrderContext(ctx, o)
 }
 
 // only returns dominos failures or non-dominos errors.
-func (o *Order) prepare() error {
+func (o *Order) prepare(ctx context.Context) error {
 	if o.cli == nil {
 		o.cli = orderClient
 	}
+	return prepareOrder(ctx, o.cli, o)
+}
 
-	odata, err := getPricingData(*o)
+func prepareOrder(ctx context.Context, c *client, o *Order) error {
+	odata, err := getPricingData(ctx, c, *o)
 	if err != nil && !IsWarning(err) {
 		return err
 	}
 	o.OrderID = odata.Order.OrderID
 
-	p, ok := odata.Order.Amounts["Customer"]
-	if ok {
+	o.breakdown = &PriceBreakdown{
+		Amounts:              odata.Order.Amounts,
+		Breakdown:            odata.Order.AmountsBreakdown,
+		EstimatedWaitMinutes: odata.Order.EstimatedWaitMinutes,
+	}
+	for _, priced := range odata.Order.Coupons {
+		for _, c := range o.Coupons {
+			if c.Code == priced.Code {
+				c.Status = priced.Status
+				c.StatusItems = priced.StatusItems
+			}
+		}
+	}
+	o.pricedKey = o.pricingKey()
+
+	if p := odata.Order.Amounts.Customer; p != 0 {
 		o.price = p
 
 		n := len(o.Payments)
@@ -171,10 +301,19 @@ func (o *Order) prepare() error {
 // ValidateOrder sends and order to the validation endpoint to be validated by
 // Dominos' servers.
 func ValidateOrder(order *Order) error {
+	return ValidateOrderContext(context.Background(), order)
+}
+
+// ValidateOrderContext is the same as ValidateOrder but with a context.
+func ValidateOrderContext(ctx context.Context, order *Order) error {
 	if order.cli == nil {
 		order.cli = orderClient
 	}
-	err := sendOrder("/power/validate-order", *order)
+	return validateOrder(ctx, order.cli, order)
+}
+
+func validateOrder(ctx context.Context, c *client, order *Order) error {
+	err := sendOrder(ctx, c, "/power/validate-order", *order)
 	if e, ok := err.(*DominosError); ok {
 		// TODO: make it possible to recognize the warning as an 'AutoAddedOrderId' warning.
 		order.OrderID = e.Order.OrderID
@@ -209,16 +348,16 @@ func (o *Order) raw() *bytes.Buffer {
 	return buf
 }
 
-func sendOrder(path string, order Order) error {
-	b, err := order.cli.post(path, nil, order.raw())
+func sendOrder(ctx context.Context, c *client, path string, order Order) error {
+	b, err := c.post(ctx, path, nil, order.raw())
 	if err != nil {
 		return err
 	}
 	return dominosErr(b)
 }
 
-func orderRequest(path string, order *Order) (map[string]interface{}, error) {
-	b, err := order.cli.post(path, nil, order.raw())
+func orderRequest(ctx context.Context, path string, order *Order) (map[string]interface{}, error) {
+	b, err := order.cli.post(ctx, path, nil, order.raw())
 	respData := map[string]interface{}{}
 
 	if err := errpair(err, json.Unmarshal(b, &respData)); err != nil {
@@ -231,12 +370,12 @@ func orderRequest(path string, order *Order) (map[string]interface{}, error) {
 func getOrderPrice(order Order) (map[string]interface{}, error) {
 	// fmt.Println("deprecated... use getPricingData")
 	order.Payments = []*orderPayment{}
-	return orderRequest("/power/price-order", &order)
+	return orderRequest(context.Background(), "/power/price-order", &order)
 }
 
-func getPricingData(order Order) (*priceingData, error) {
+func getPricingData(ctx context.Context, c *client, order Order) (*priceingData, error) {
 	order.Payments = []*orderPayment{}
-	b, err := order.cli.post("/power/price-order", nil, order.raw())
+	b, err := c.post(ctx, "/power/price-order", nil, order.raw())
 	resp := &priceingData{}
 	if err := errpair(err, json.Unmarshal(b, resp)); err != nil {
 		return nil, err
@@ -249,10 +388,13 @@ type priceingData struct {
 }
 
 type pricedOrder struct {
-	OrderID          string
-	Amounts          map[string]float64
-	AmountsBreakdown map[string]interface{}
-	PulseOrderGUID   string `json:"PulseOrderGuid"`
+	OrderID              string
+	Amounts              Amounts
+	AmountsBreakdown     AmountsBreakdown
+	EstimatedWaitMinutes string
+	Coupons              []*OrderCoupon
+	PulseOrderGUID       string `json:"PulseOrderGuid"`
+	FutureOrderTime      string
 }
 
 // OrderProduct represents an item that will be sent to and from dominos within
@@ -270,21 +412,42 @@ type OrderProduct struct {
 	IsNew              bool                   `json:"isNew"`
 	NeedsCustomization bool                   `json:"NeedsCustomization"`
 	Opts               map[string]interface{} `json:"Options"`
-	other              map[string]interface{}
-	pType              string
+
+	// SideOpts are the sides (dipping cups, dressings, etc.) sent with the
+	// product, see OrderProduct.Sides.
+	SideOpts map[string]int `json:"Sides,omitempty"`
+
+	other      map[string]interface{}
+	pType      string
+	availSides []string
 }
 
 // OrderProductFromItem will construct an order product from an Item.
 func OrderProductFromItem(itm Item) *OrderProduct {
-	return &OrderProduct{
+	code := itm.ItemCode()
+	if pc, ok := itm.(*PreConfiguredProduct); ok && pc.variant != nil {
+		// dominos orders pre-configured products by their variant code
+		code = pc.variant.Code
+	}
+	p := &OrderProduct{
 		ItemCommon: ItemCommon{
-			Code: itm.ItemCode(),
+			Code: code,
 			Name: itm.ItemName(),
 		},
 		Qty:   1,
 		Opts:  itm.Options(),
 		pType: itm.Category(),
 	}
+	if si, ok := itm.(SideItem); ok && len(si.Sides()) > 0 {
+		p.SideOpts = make(map[string]int)
+		for code, qty := range si.Sides() {
+			p.SideOpts[code] = qty
+		}
+	}
+	if sl, ok := itm.(sideLister); ok {
+		p.availSides = sl.availableSides()
+	}
+	return p
 }
 
 // Options returns a map of the OrderProdut's options.
@@ -297,6 +460,12 @@ func (p *OrderProduct) Category() string {
 	return p.pType
 }
 
+// SetMenu gives the product a menu to find the names of its toppings when
+// ReadableOptions is called.
+func (p *OrderProduct) SetMenu(m *Menu) {
+	p.menu = m
+}
+
 // ReadableOptions gives the options that are meant for humans to view.
 func (p *OrderProduct) ReadableOptions() map[string]string {
 	if p.menu != nil { // this menu that is passed along with item is temporary
@@ -311,9 +480,12 @@ func (p *OrderProduct) ReadableOptions() map[string]string {
 // pizza. The 'amount' parameter is 2.0, 1.5, 1.0, o.5, or 0 and gives the amount
 // of topping should be given.
 func (p *OrderProduct) AddTopping(code, coverage, amount string) error {
-	top := makeTopping(coverage, amount, nil)
-	if top == nil {
-		return fmt.Errorf("could not make %s topping", code)
+	top, err := makeTopping(coverage, amount, productOptQtys[p.pType])
+	if err != nil {
+		return &ToppingError{Topping: code, Item: p.Code, Err: err}
+	}
+	if p.Opts == nil {
+		p.Opts = make(map[string]interface{})
 	}
 	p.Opts[code] = top
 	return nil
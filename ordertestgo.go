@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
@@ -124,7 +125,7 @@ func TestPrepareOrder(t *testing.T) {
 
 	menu := testingMenu()
 	tests.Check(o.AddProduct(menu.FindItem("10SCREEN")))
-	tests.Check(o.prepare())
+	tests.Check(o.prepare(context.Background()))
 	if o.price <= 0.0 {
 		t.Error("cached price should not be zero or less")
 	}
@@ -359,14 +360,14 @@ func TestOrderToJSON(t *testing.T) {
 func TestOrderCalls(t *testing.T) {
 	o := new(Order)
 	o.Init()
-	err := sendOrder("/power/validate-order", *o)
+	err := sendOrder(context.Background(), o.cli, "/power/validate-order", *o)
 	if !IsFailure(err) || err == nil {
 		t.Error("expected error")
 	}
 
 	o = new(Order)
 	InitOrder(o)
-	err = sendOrder("", *o)
+	err = sendOrder(context.Background(), o.cli, "", *o)
 	if err == nil {
 		t.Error("expected error")
 	}
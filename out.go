@@ -121,6 +121,29 @@ func PrintOrder(o *dawg.Order, full, color, price bool) (err error) {
 	return errs.Pair(err, tmpl(output, t, data))
 }
 
+// PrintReceipt will print an itemized price of the order.
+func PrintReceipt(o *dawg.Order, color bool) error {
+	b, err := o.PriceBreakdown()
+	if err != nil {
+		return err
+	}
+	var keycolor, endcolor string
+	if color {
+		keycolor = "\033[01;34m"
+		endcolor = "\033[0m"
+	}
+	data := struct {
+		*dawg.PriceBreakdown
+		KeyColor string
+		EndColor string
+	}{
+		PriceBreakdown: b,
+		KeyColor:       keycolor,
+		EndColor:       endcolor,
+	}
+	return tmpl(output, receiptTmpl, data)
+}
+
 // PrintVariant will display a dawg.Variant in a pretty way.
 func PrintVariant(v *dawg.Variant, verbose bool) error {
 	var template string
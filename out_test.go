@@ -39,6 +39,33 @@ func TestTmpl(t *testing.T) {
 	}
 }
 
+func TestReceiptTmpl(t *testing.T) {
+	b := &dawg.PriceBreakdown{EstimatedWaitMinutes: "20-30"}
+	b.Amounts.Customer = 41.62
+	b.Breakdown.FoodAndBeverage = 33.93
+	b.Breakdown.DeliveryFee = 3.99
+	b.Breakdown.Savings = 2
+	b.Breakdown.Tax = 3.7
+	data := struct {
+		*dawg.PriceBreakdown
+		KeyColor, EndColor string
+	}{PriceBreakdown: b}
+
+	buf := new(bytes.Buffer)
+	if err := tmpl(buf, receiptTmpl, data); err != nil {
+		t.Fatal(err)
+	}
+	expected := `  receipt:
+    food and beverage: $33.93
+    delivery fee:      $3.99
+    savings:          -$2.00
+    tax:               $3.70
+    total:             $41.62
+  estimated wait: 20-30 minutes
+`
+	tests.Compare(t, buf.String(), expected)
+}
+
 var testStore *dawg.Store
 
 func init() {
@@ -0,0 +1,165 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Payment types used by dominos. A store lists the payment types that it
+// accepts in Store.PaymentTypes.
+const (
+	// CashPayment is used for paying in cash at the door or at the store.
+	CashPayment = "Cash"
+	// CreditCardPayment is used for new and saved credit cards.
+	CreditCardPayment = "CreditCard"
+	// GiftCardPayment is used for dominos gift cards.
+	GiftCardPayment = "GiftCard"
+)
+
+// PaymentMethod is a way of paying for an order, see Order.AddPaymentMethod.
+//
+// The payment methods in this package are Payment for a new credit card,
+// UserCard for a card that is saved to an account, GiftCard, and Cash.
+type PaymentMethod interface {
+	// PaymentType returns the type of payment as dominos names it.
+	PaymentType() string
+
+	toOrderPayment() *orderPayment
+}
+
+// PaymentType returns the payment type for a credit card, see PaymentMethod.
+func (p *Payment) PaymentType() string {
+	return CreditCardPayment
+}
+
+func (p *Payment) toOrderPayment() *orderPayment {
+	return makeOrderPaymentFromCard(p)
+}
+
+func (p *Payment) cardType() string {
+	return findCardType(p.Number)
+}
+
+// PaymentType returns the payment type for a saved card, see PaymentMethod.
+func (c *UserCard) PaymentType() string {
+	return CreditCardPayment
+}
+
+func (c *UserCard) toOrderPayment() *orderPayment {
+	exp := time.Date(c.ExpirationYear, time.Month(c.ExpirationMonth), 1, 0, 0, 0, 0, time.Local)
+	return &orderPayment{
+		Type:       CreditCardPayment,
+		CardID:     c.ID,
+		CardType:   c.CardType,
+		Expiration: formatDate(exp),
+		PostalCode: c.BillingZip,
+	}
+}
+
+func (c *UserCard) cardType() string {
+	return c.CardType
+}
+
+// GiftCard is a dominos gift card used to pay for an order.
+type GiftCard struct {
+	Number string
+	Pin    string
+}
+
+// PaymentType returns the payment type for a gift card, see PaymentMethod.
+func (g *GiftCard) PaymentType() string {
+	return GiftCardPayment
+}
+
+func (g *GiftCard) toOrderPayment() *orderPayment {
+	return &orderPayment{
+		Type:         GiftCardPayment,
+		Number:       g.Number,
+		SecurityCode: g.Pin,
+	}
+}
+
+// Cash is used to pay for an order with cash when it is delivered or picked
+// up.
+type Cash struct{}
+
+// PaymentType returns the payment type for cash, see PaymentMethod.
+func (Cash) PaymentType() string {
+	return CashPayment
+}
+
+func (Cash) toOrderPayment() *orderPayment {
+	return &orderPayment{Type: CashPayment}
+}
+
+// AddPaymentMethod adds a payment method to the order.
+//
+// Saved cards can only be used by the account that saved them, so adding a
+// UserCard that came from UserProfile.Cards will also send the order as that
+// user.
+func (o *Order) AddPaymentMethod(p PaymentMethod) error {
+	if p == nil {
+		return errors.New("cannot add a nil payment method")
+	}
+	if c, ok := p.(*UserCard); ok {
+		if c.ID == "" {
+			return errors.New("saved card has no card id")
+		}
+		if c.user != nil {
+			o.SetCustomer(c.user)
+		}
+	}
+	o.Payments = append(o.Payments, p.toOrderPayment())
+	return nil
+}
+
+// AcceptsPayment returns an error if the store does not accept a payment
+// method.
+func (s *Store) AcceptsPayment(p PaymentMethod) error {
+	if len(s.PaymentTypes) > 0 && !contains(s.PaymentTypes, p.PaymentType()) {
+		return fmt.Errorf("store %s does not accept %s payments", s.ID, p.PaymentType())
+	}
+	switch p.(type) {
+	case *GiftCard:
+		if !s.AcceptGiftCards {
+			return fmt.Errorf("store %s does not accept gift cards", s.ID)
+		}
+	case *UserCard:
+		if !s.AcceptSavedCards {
+			return fmt.Errorf("store %s does not accept saved cards", s.ID)
+		}
+	case *Payment:
+		if !s.AcceptAnonymousCards {
+			return fmt.Errorf("store %s only accepts cards saved to an account", s.ID)
+		}
+	}
+
+	if c, ok := p.(interface{ cardType() string }); ok && c.cardType() != "" && len(s.CreditCardTypes) > 0 {
+		want := normalizeCardType(c.cardType())
+		for _, accepted := range s.CreditCardTypes {
+			if normalizeCardType(accepted) == want {
+				return nil
+			}
+		}
+		return fmt.Errorf("store %s does not accept %s cards", s.ID, c.cardType())
+	}
+	return nil
+}
+
+// normalizeCardType is used to compare card types because the store will
+// send names like "Discover Card" and "Mastercard" where the card types that
+// are found from the card number look like "Discover" and "MasterCard".
+func normalizeCardType(t string) string {
+	t = strings.ToLower(strings.Replace(t, " ", "", -1))
+	return strings.TrimSuffix(t, "card")
+}
+
+// interface checks
+var (
+	_ PaymentMethod = (*Payment)(nil)
+	_ PaymentMethod = (*UserCard)(nil)
+	_ PaymentMethod = (*GiftCard)(nil)
+	_ PaymentMethod = Cash{}
+)
@@ -0,0 +1,78 @@
+package dawg
+
+import "testing"
+
+func TestPaymentMethods(t *testing.T) {
+	user := &UserProfile{ID: "user-id", cli: orderClient}
+	saved := &UserCard{ID: "card-id", NickName: "work", CardType: "VISA",
+		ExpirationMonth: 3, ExpirationYear: 2030, BillingZip: "12345", user: user}
+
+	o := &Order{}
+	for _, p := range []PaymentMethod{
+		NewCard("4111111111111111", "01/30", 123).(*Payment),
+		saved,
+		&GiftCard{Number: "6000", Pin: "1234"},
+		Cash{},
+	} {
+		if err := o.AddPaymentMethod(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := o.AddPaymentMethod(nil); err == nil {
+		t.Error("expected an error for a nil payment method")
+	}
+	if err := o.AddPaymentMethod(&UserCard{}); err == nil {
+		t.Error("expected an error for a saved card without an id")
+	}
+
+	if len(o.Payments) != 4 {
+		t.Fatalf("expected 4 payments, got %d", len(o.Payments))
+	}
+	for i, tc := range []struct {
+		typ, number, cardID, exp string
+	}{
+		{typ: CreditCardPayment, number: "4111111111111111", exp: "0130"},
+		{typ: CreditCardPayment, cardID: "card-id", exp: "0330"},
+		{typ: GiftCardPayment, number: "6000"},
+		{typ: CashPayment},
+	} {
+		p := o.Payments[i]
+		if p.Type != tc.typ || p.Number != tc.number || p.CardID != tc.cardID || p.Expiration != tc.exp {
+			t.Errorf("wrong payment %d: %+v", i, p)
+		}
+	}
+	if o.Payments[1].PostalCode != "12345" {
+		t.Error("saved card should use its billing zip")
+	}
+	if o.Payments[2].SecurityCode != "1234" {
+		t.Error("gift card pin should be sent as the security code")
+	}
+	if o.CustomerID != "user-id" {
+		t.Error("order should be sent as the user that saved the card")
+	}
+}
+
+func TestStore_AcceptsPayment(t *testing.T) {
+	s := storeFromFile(t)
+	visa := NewCard("4111111111111111", "01/30", 123).(*Payment)
+	jcb := NewCard("3530111333300000", "01/30", 123).(*Payment)
+	saved := &UserCard{ID: "1", CardType: "Mastercard"}
+
+	for _, p := range []PaymentMethod{visa, saved, &GiftCard{}, Cash{}} {
+		if err := s.AcceptsPayment(p); err != nil {
+			t.Errorf("store should accept %T: %v", p, err)
+		}
+	}
+	if err := s.AcceptsPayment(jcb); err == nil {
+		t.Error("store should not accept JCB cards")
+	}
+
+	s.PaymentTypes = []string{CreditCardPayment}
+	if err := s.AcceptsPayment(Cash{}); err == nil {
+		t.Error("store should not accept cash")
+	}
+	s.AcceptSavedCards = false
+	if err := s.AcceptsPayment(saved); err == nil {
+		t.Error("store should not accept saved cards")
+	}
+}
@@ -0,0 +1,109 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PriceBreakdown is the itemized price of an order as calculated by dominos.
+type PriceBreakdown struct {
+	Amounts   Amounts
+	Breakdown AmountsBreakdown `json:"AmountsBreakdown"`
+
+	// EstimatedWaitMinutes is the range of minutes that dominos expects the
+	// order to take, formatted like "20-30". See PriceBreakdown.EstimatedWait.
+	EstimatedWaitMinutes string
+}
+
+// Amounts are the totals that dominos uses to price an order.
+type Amounts struct {
+	Adjustment float64
+	Bottle     float64
+	Customer   float64
+	Discount   float64
+	Menu       float64
+	Net        float64
+	Payment    float64
+	Surcharge  float64
+	Tax        float64
+	Tax1       float64
+	Tax2       float64
+}
+
+// AmountsBreakdown is the price of an order split up the way it would be on a
+// receipt.
+type AmountsBreakdown struct {
+	Adjustment      float64
+	Bottle          float64
+	Customer        float64
+	DeliveryFee     float64
+	FoodAndBeverage float64
+	Savings         float64
+	Surcharge       float64
+	Tax             float64
+	Tax1            float64
+	Tax2            float64
+}
+
+// UnmarshalJSON decodes the breakdown, dominos sends some of these values as
+// strings and some as numbers.
+func (a *AmountsBreakdown) UnmarshalJSON(b []byte) error {
+	raw := map[string]number{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*a = AmountsBreakdown{
+		Adjustment:      float64(raw["Adjustment"]),
+		Bottle:          float64(raw["Bottle"]),
+		Customer:        float64(raw["Customer"]),
+		DeliveryFee:     float64(raw["DeliveryFee"]),
+		FoodAndBeverage: float64(raw["FoodAndBeverage"]),
+		Savings:         float64(raw["Savings"]),
+		Surcharge:       float64(raw["Surcharge"]),
+		Tax:             float64(raw["Tax"]),
+		Tax1:            float64(raw["Tax1"]),
+		Tax2:            float64(raw["Tax2"]),
+	}
+	return nil
+}
+
+// EstimatedWait parses EstimatedWaitMinutes into a minimum and maximum wait
+// time.
+func (p *PriceBreakdown) EstimatedWait() (min, max time.Duration, err error) {
+	return parseWaitRange(p.EstimatedWaitMinutes)
+}
+
+func parseWaitRange(s string) (min, max time.Duration, err error) {
+	parts := strings.SplitN(s, "-", 2)
+	lo, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("bad wait time %q", s)
+	}
+	hi := lo
+	if len(parts) == 2 {
+		if hi, err = strconv.Atoi(strings.TrimSpace(parts[1])); err != nil {
+			return 0, 0, fmt.Errorf("bad wait time %q", s)
+		}
+	}
+	return time.Duration(lo) * time.Minute, time.Duration(hi) * time.Minute, nil
+}
+
+// number is a float that can be decoded from a json string.
+type number float64
+
+func (n *number) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*n = 0
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+	*n = number(f)
+	return nil
+}
@@ -0,0 +1,97 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAmountsBreakdown(t *testing.T) {
+	b := []byte(`{"Adjustment":"0.00","Bottle":0,"Customer":41.62,"DeliveryFee":"3.99",
+		"FoodAndBeverage":"33.93","Savings":"1.50","Surcharge":"0.00","Tax":3.7,"Tax1":3.7,"Tax2":null}`)
+	a := AmountsBreakdown{}
+	if err := json.Unmarshal(b, &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.DeliveryFee != 3.99 || a.FoodAndBeverage != 33.93 || a.Savings != 1.5 {
+		t.Errorf("string amounts not decoded: %+v", a)
+	}
+	if a.Customer != 41.62 || a.Tax != 3.7 {
+		t.Errorf("number amounts not decoded: %+v", a)
+	}
+	if err := json.Unmarshal([]byte(`{"Tax":"abc"}`), &a); err == nil {
+		t.Error("expected an error for a bad amount")
+	}
+}
+
+func TestEstimatedWait(t *testing.T) {
+	for _, tc := range []struct {
+		in       string
+		min, max time.Duration
+		err      bool
+	}{
+		{"41-51", 41 * time.Minute, 51 * time.Minute, false},
+		{" 20 - 30 ", 20 * time.Minute, 30 * time.Minute, false},
+		{"15", 15 * time.Minute, 15 * time.Minute, false},
+		{"", 0, 0, true},
+		{"10-x", 0, 0, true},
+	} {
+		p := &PriceBreakdown{EstimatedWaitMinutes: tc.in}
+		min, max, err := p.EstimatedWait()
+		if (err != nil) != tc.err {
+			t.Errorf("%q: unexpected error value: %v", tc.in, err)
+			continue
+		}
+		if min != tc.min || max != tc.max {
+			t.Errorf("%q: got %v-%v, want %v-%v", tc.in, min, max, tc.min, tc.max)
+		}
+	}
+}
+
+func TestPriceBreakdown(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"Status":0,"Order":{"OrderID":"1","EstimatedWaitMinutes":"20-30",
+			"Amounts":{"Customer":%d.5,"Menu":10,"Discount":0,"Tax":1},
+			"AmountsBreakdown":{"Customer":%[1]d.5,"DeliveryFee":"3.99","FoodAndBeverage":"10.00","Tax":1}}}`, calls*10)
+	}))
+	defer srv.Close()
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	o := &Order{ServiceMethod: Delivery, cli: c.cli}
+	p := &OrderProduct{ItemCommon: ItemCommon{Code: "12SCREEN"}, Qty: 1}
+	o.Products = append(o.Products, p)
+
+	b, err := o.PriceBreakdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Amounts.Customer != 10.5 || b.Breakdown.DeliveryFee != 3.99 || b.Breakdown.FoodAndBeverage != 10 {
+		t.Errorf("wrong breakdown: %+v", b)
+	}
+	if b.EstimatedWaitMinutes != "20-30" {
+		t.Errorf("wrong wait time %q", b.EstimatedWaitMinutes)
+	}
+	if price, _ := o.Price(); price != 10.5 || calls != 1 {
+		t.Errorf("price should be cached: got %v after %d requests", price, calls)
+	}
+
+	// changing a product in place should still give a new price
+	p.Qty = 2
+	if price, _ := o.Price(); price != 20.5 || calls != 2 {
+		t.Errorf("price was stale: got %v after %d requests", price, calls)
+	}
+	if err = o.RemoveProduct("12SCREEN"); err != nil {
+		t.Fatal(err)
+	}
+	if o.price != 0 || o.breakdown != nil {
+		t.Error("removing a product should clear the cached price")
+	}
+}
@@ -0,0 +1,149 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Save will send the user's name, phone number, email, sms and email opt-ins,
+// and saved addresses to dominos so that they are updated on the account.
+func (u *UserProfile) Save() error {
+	return u.SaveContext(context.Background())
+}
+
+// SaveContext is the same as Save but with a context.
+func (u *UserProfile) SaveContext(ctx context.Context) error {
+	if u.Email == "" {
+		return errors.New("cannot save a profile without an email")
+	}
+	addrs := u.Addresses
+	if addrs == nil {
+		addrs = []*UserAddress{}
+	}
+	body := &profileUpdate{
+		CustomerID: u.ID,
+		FirstName:  u.FirstName,
+		LastName:   u.LastName,
+		Email:      u.Email,
+		Phone:      u.Phone,
+		EmailOptIn: u.EmailOptIn,
+		SmsOptIn:   u.SmsOptIn,
+		SmsPhone:   u.SmsPhone,
+		Addresses:  addrs,
+	}
+	return u.customerRequest(ctx, "PUT", "", body, nil)
+}
+
+type profileUpdate struct {
+	CustomerID string
+	FirstName  string
+	LastName   string
+	Email      string
+	Phone      string
+	EmailOptIn bool
+	SmsOptIn   bool
+	SmsPhone   string
+	Addresses  []*UserAddress
+}
+
+// FindAddress gets one of the user's saved addresses by its name. Case is
+// ignored when comparing names.
+func (u *UserProfile) FindAddress(name string) (*UserAddress, error) {
+	if i := u.addressIndex(name); i >= 0 {
+		return u.Addresses[i], nil
+	}
+	return nil, fmt.Errorf("no saved address named '%s'", name)
+}
+
+// SaveAddress will save an address to the dominos account with a name. If the
+// account already has an address with that name then it is replaced.
+func (u *UserProfile) SaveAddress(a Address, name string) (*UserAddress, error) {
+	return u.SaveAddressContext(context.Background(), a, name)
+}
+
+// SaveAddressContext is the same as SaveAddress but with a context.
+func (u *UserProfile) SaveAddressContext(ctx context.Context, a Address, name string) (*UserAddress, error) {
+	if a == nil {
+		return nil, errors.New("cannot save a nil address")
+	}
+	if name == "" {
+		return nil, errors.New("saved addresses need a name")
+	}
+	if ua, ok := a.(*UserAddress); ok {
+		// copy it so the caller's address is left alone if the save fails
+		cp := *ua
+		a = &cp
+	}
+	addr := UserAddressFromAddress(a)
+	addr.Name = name
+	addrs := make([]*UserAddress, len(u.Addresses), len(u.Addresses)+1)
+	copy(addrs, u.Addresses)
+	if i := u.addressIndex(name); i >= 0 {
+		addr.IsDefault = addr.IsDefault || addrs[i].IsDefault
+		addrs[i] = addr
+	} else {
+		addrs = append(addrs, addr)
+	}
+	return addr, u.saveAddresses(ctx, addrs)
+}
+
+// RemoveAddress will remove an address from the dominos account.
+func (u *UserProfile) RemoveAddress(name string) error {
+	return u.RemoveAddressContext(context.Background(), name)
+}
+
+// RemoveAddressContext is the same as RemoveAddress but with a context.
+func (u *UserProfile) RemoveAddressContext(ctx context.Context, name string) error {
+	i := u.addressIndex(name)
+	if i < 0 {
+		return fmt.Errorf("no saved address named '%s'", name)
+	}
+	addrs := make([]*UserAddress, 0, len(u.Addresses)-1)
+	addrs = append(addrs, u.Addresses[:i]...)
+	addrs = append(addrs, u.Addresses[i+1:]...)
+	return u.saveAddresses(ctx, addrs)
+}
+
+// SetDefaultAddress will make one of the saved addresses the account's
+// default address.
+func (u *UserProfile) SetDefaultAddress(name string) error {
+	return u.SetDefaultAddressContext(context.Background(), name)
+}
+
+// SetDefaultAddressContext is the same as SetDefaultAddress but with a context.
+func (u *UserProfile) SetDefaultAddressContext(ctx context.Context, name string) error {
+	i := u.addressIndex(name)
+	if i < 0 {
+		return fmt.Errorf("no saved address named '%s'", name)
+	}
+	addrs := make([]*UserAddress, len(u.Addresses))
+	for j, a := range u.Addresses {
+		cp := *a
+		cp.IsDefault = i == j
+		addrs[j] = &cp
+	}
+	return u.saveAddresses(ctx, addrs)
+}
+
+// saveAddresses saves the profile with a new list of addresses. The user's
+// addresses are only changed if dominos accepted them.
+func (u *UserProfile) saveAddresses(ctx context.Context, addrs []*UserAddress) error {
+	old := u.Addresses
+	u.Addresses = addrs
+	if err := u.SaveContext(ctx); err != nil {
+		u.Addresses = old
+		return err
+	}
+	return nil
+}
+
+func (u *UserProfile) addressIndex(name string) int {
+	for i, a := range u.Addresses {
+		if strings.EqualFold(a.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
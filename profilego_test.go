@@ -0,0 +1,99 @@
+package dawg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserProfile_Save(t *testing.T) {
+	var (
+		saved profileUpdate
+		fail  bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/power/customer/user" {
+			t.Errorf("wrong request: %s %s", r.Method, r.URL.Path)
+		}
+		if fail {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		saved = profileUpdate{}
+		if err := json.NewDecoder(r.Body).Decode(&saved); err != nil {
+			t.Error(err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &UserProfile{ID: "user", FirstName: "Jimmy", Email: "jimmy@example.com", cli: c.cli}
+	user.SmsOptIn = true
+	if err = user.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if saved.FirstName != "Jimmy" || !saved.SmsOptIn || saved.Addresses == nil {
+		t.Errorf("wrong profile sent: %+v", saved)
+	}
+
+	if _, err = user.SaveAddress(testAddress(), "home"); err != nil {
+		t.Fatal(err)
+	}
+	work := &UserAddress{Street: "1 Main St", CityName: "Springfield", Region: "IL", PostalCode: "62701"}
+	if _, err = user.SaveAddress(work, "Work"); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Addresses) != 2 || saved.Addresses[1].Name != "Work" {
+		t.Fatalf("wrong addresses sent: %+v", saved.Addresses)
+	}
+	if err = user.SetDefaultAddress("work"); err != nil {
+		t.Fatal(err)
+	}
+	if !saved.Addresses[1].IsDefault || saved.Addresses[0].IsDefault {
+		t.Error("work should be the only default address")
+	}
+	if addr := user.DefaultAddress(); addr == nil || addr.Name != "Work" {
+		t.Errorf("wrong default address: %+v", addr)
+	}
+	work = &UserAddress{Street: "2 Main St", CityName: "Springfield", Region: "IL", PostalCode: "62701"}
+	if _, err = user.SaveAddress(work, "work"); err != nil {
+		t.Fatal(err)
+	}
+	if len(user.Addresses) != 2 || !user.Addresses[1].IsDefault || user.Addresses[1].Street != "2 Main St" {
+		t.Errorf("address was not updated: %+v", user.Addresses[1])
+	}
+
+	fail = true
+	if err = user.RemoveAddress("home"); err == nil {
+		t.Error("expected an error from a failed request")
+	}
+	if len(user.Addresses) != 2 {
+		t.Error("addresses should not change when the profile is not saved")
+	}
+	other := &UserAddress{Street: "3 Main St", CityName: "Springfield", Region: "IL", PostalCode: "62701"}
+	if _, err = user.SaveAddress(other, "other"); err == nil {
+		t.Error("expected an error from a failed request")
+	}
+	if other.Name != "" || other.StreetNumber != "" || other.StreetName != "" {
+		t.Errorf("the address given to SaveAddress should not be changed: %+v", other)
+	}
+	fail = false
+	if err = user.RemoveAddress("home"); err != nil {
+		t.Fatal(err)
+	}
+	if len(saved.Addresses) != 1 || len(user.Addresses) != 1 {
+		t.Errorf("address was not removed: %+v", saved.Addresses)
+	}
+	if err = user.RemoveAddress("home"); err == nil {
+		t.Error("expected an error for a missing address")
+	}
+	if _, err = user.FindAddress("WORK"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/spf13/cobra"
+)
+
+// NewReorderCmd creates a new reorder command.
+func NewReorderCmd(b cli.Builder) cli.CliCommand {
+	c := &reorderCmd{db: b.DB()}
+	c.CliCommand = b.Build("reorder [n|easy]", "Copy a previous order into the cart.", c)
+	c.Cmd().Long = `The reorder command copies one of the signed in account's previous
+orders into the cart so that it can be changed or sent again.
+
+Give the number of the order to copy where 1 is the most recent order, or
+'easy' for the account's easy order. The most recent order is used by default.
+
+The products are checked against the store's current menu. Products that have
+a new code are replaced and products that are no longer sold are left out.`
+
+	c.Flags().StringVarP(&c.name, "name", "n", "", "the name of the new order in the cart")
+	return c
+}
+
+// `apizza reorder`
+type reorderCmd struct {
+	cli.CliCommand
+	db   *cache.DataBase
+	name string
+}
+
+func (c *reorderCmd) Run(cmd *cobra.Command, args []string) error {
+	if len(args) > 1 {
+		return errors.New("give only one previous order")
+	}
+	user, err := signIn(c.db)
+	if err != nil {
+		return err
+	}
+
+	var prev *dawg.EasyOrder
+	if len(args) == 1 && args[0] == "easy" {
+		if prev, err = user.GetEasyOrder(); err != nil {
+			return err
+		}
+		if prev == nil {
+			return errors.New("the account has no easy order")
+		}
+	} else {
+		n := 1
+		if len(args) == 1 {
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("'%s' is not an order number or 'easy'", args[0])
+			}
+		}
+		orders, err := user.PreviousOrders(n)
+		if err != nil {
+			return err
+		}
+		if n > len(orders) {
+			return fmt.Errorf("the account only has %d previous order(s)", len(orders))
+		}
+		prev = orders[n-1]
+	}
+
+	order, report, err := prev.ToOrder(user)
+	if err != nil {
+		return err
+	}
+	order.OrderName = eitherOr(c.name, eitherOr(order.OrderName, "reorder"))
+	if _, err = data.GetOrder(order.Name(), c.db); err == nil {
+		return fmt.Errorf("an order named '%s' is already in the cart (see --name)", order.Name())
+	}
+	if len(order.Products) == 0 {
+		return errors.New("none of the products in the order are on the menu")
+	}
+	if err = data.SaveOrder(order, c.Output(), c.db); err != nil {
+		return err
+	}
+	printReorderReport(c.Output(), report)
+	return nil
+}
+
+func printReorderReport(w io.Writer, r *dawg.ReorderReport) {
+	if !r.Changed() {
+		return
+	}
+	fmt.Fprintln(w, "the order has changed since it was last sent:")
+	old := make([]string, 0, len(r.Replaced))
+	for code := range r.Replaced {
+		old = append(old, code)
+	}
+	sort.Strings(old)
+	for _, code := range old {
+		fmt.Fprintf(w, "  %s was replaced with %s\n", code, r.Replaced[code])
+	}
+	for _, code := range r.Missing {
+		fmt.Fprintf(w, "  %s is no longer on the menu\n", code)
+	}
+}
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+func TestReorderCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/customer/user/order", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{
+			"customerOrders": [{"order": {"ServiceMethod": "Carryout", "StoreID": "4336",
+				"Products": [{"Code": "GONE", "name": "A Pizza That Is Gone", "Qty": 1}]}}],
+			"easyOrder": {"easyOrderNickName": "usual", "order": {"ServiceMethod": "Carryout", "StoreID": "4336",
+				"Products": [
+					{"Code": "14SCREEN", "Qty": 2},
+					{"Code": "OLDCODE", "name": "Medium Hand Tossed Pizza", "Qty": 1}
+				]}}
+		}`)
+	})
+	mux.HandleFunc("/power/store/4336/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"StoreID": "4336", "Status": 0}`)
+	})
+	mux.HandleFunc("/power/store/4336/menu", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Variants": {
+			"14SCREEN": {"Code": "14SCREEN", "Name": "Large Hand Tossed Pizza", "ProductCode": "S_PIZZA", "Price": "13.99"},
+			"12SCREEN": {"Code": "12SCREEN", "Name": "Medium Hand Tossed Pizza", "ProductCode": "S_PIZZA", "Price": "11.99"}
+		}}`)
+	})
+	mux.HandleFunc("/power/validate-order", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Status": 0, "Order": {}}`)
+	})
+	defer testAccount(t, r.DataBase, mux)()
+
+	cmd := NewReorderCmd(r)
+	tests.Check(cmd.Run(cmd.Cmd(), []string{"easy"}))
+	tests.Compare(t, r.Out.String(), `order successfully updated.
+the order has changed since it was last sent:
+  OLDCODE was replaced with 12SCREEN
+`)
+	o, err := data.GetOrder("usual", r.DataBase)
+	tests.Check(err)
+	if o.CustomerID != "user" || len(o.Products) != 2 {
+		t.Fatalf("wrong order saved: %+v", o)
+	}
+	if o.Products[0].Code != "14SCREEN" || o.Products[0].Qty != 2 || o.Products[1].Code != "12SCREEN" {
+		t.Errorf("wrong products: %+v %+v", o.Products[0], o.Products[1])
+	}
+
+	err = cmd.Run(cmd.Cmd(), []string{"easy"})
+	if err == nil || !strings.Contains(err.Error(), "an order named 'usual' is already in the cart") {
+		t.Errorf("expected an error for an order name that is taken, got %v", err)
+	}
+	err = cmd.Run(cmd.Cmd(), []string{"2"})
+	if err == nil || err.Error() != "the account only has 1 previous order(s)" {
+		t.Errorf("expected an error for an order number that is out of range, got %v", err)
+	}
+	for _, arg := range []string{"0", "first"} {
+		tests.Exp(cmd.Run(cmd.Cmd(), []string{arg}), "expected an error for order", arg)
+	}
+	err = cmd.Run(cmd.Cmd(), []string{"1"})
+	if err == nil || err.Error() != "none of the products in the order are on the menu" {
+		t.Errorf("expected an error for an order with nothing on the menu, got %v", err)
+	}
+	if _, err = data.GetOrder("reorder", r.DataBase); err == nil {
+		t.Error("an empty order should not be saved")
+	}
+}
@@ -0,0 +1,166 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ReorderReport describes how a previous order changed when it was rebuilt
+// with EasyOrder.ToOrder.
+type ReorderReport struct {
+	// Replaced maps the codes of products that are no longer on the menu to
+	// the codes of the products that replaced them.
+	Replaced map[string]string
+	// Missing holds the codes of products that could not be found on the
+	// menu. They are left out of the new order.
+	Missing []string
+}
+
+// Changed returns true if the new order is not the same as the previous one.
+func (r *ReorderReport) Changed() bool {
+	return len(r.Replaced) > 0 || len(r.Missing) > 0
+}
+
+// ToOrder rebuilds a previous order so that it can be sent again. The
+// products, address, and service method are checked against the store that
+// would take the order today. Delivery orders go to the store nearest to the
+// previous address and carryout orders go to the user's store if one has been
+// set or the previous store if not.
+//
+// Product codes that are no longer on the menu are replaced by a product with
+// the same name if one can be found, all changes are listed in the report.
+// Coupons and payments are not copied to the new order.
+func (e *EasyOrder) ToOrder(u *UserProfile) (*Order, *ReorderReport, error) {
+	return e.ToOrderContext(context.Background(), u)
+}
+
+// ToOrderContext is the same as ToOrder but with a context.
+func (e *EasyOrder) ToOrderContext(ctx context.Context, u *UserProfile) (*Order, *ReorderReport, error) {
+	prev := &e.Order.Order
+	if len(prev.Products) == 0 {
+		return nil, nil, errors.New("previous order has no products")
+	}
+	service := prev.ServiceMethod
+	if service == "" {
+		service = u.ServiceMethod
+	}
+	if service == "" {
+		service = Delivery
+	}
+
+	var (
+		store *Store
+		err   error
+		addr  Address
+	)
+	if prev.Address != nil {
+		addr = prev.Address
+	}
+	if service == Delivery {
+		if addr == nil || addr.LineOne() == "" {
+			def := u.DefaultAddress()
+			if def == nil {
+				return nil, nil, errors.New("no address to deliver the order to")
+			}
+			addr = def
+		}
+		store, err = getNearestStore(ctx, u.cli, addr, service)
+	} else if u.store != nil {
+		store = u.store
+	} else {
+		store, err = newStore(ctx, u.cli, prev.StoreID, service, addr)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	menu, err := store.MenuContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	order := &Order{
+		FirstName:     either(u.FirstName, prev.FirstName),
+		LastName:      either(u.LastName, prev.LastName),
+		Email:         either(u.Email, prev.Email),
+		Phone:         either(u.Phone, prev.Phone),
+		LanguageCode:  u.cli.language(),
+		ServiceMethod: service,
+		StoreID:       store.ID,
+		Products:      []*OrderProduct{},
+		Payments:      []*orderPayment{},
+		Coupons:       []*OrderCoupon{},
+		OrderName:     e.OrderNickName,
+	}
+	if addr != nil {
+		order.Address = StreetAddrFromAddress(addr)
+	}
+	order.SetCustomer(u)
+	report := &ReorderReport{Replaced: map[string]string{}}
+	rebuildProducts(menu, prev.Products, order, report)
+	return order, report, nil
+}
+
+func either(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a
+}
+
+// rebuildProducts adds copies of the previous products to the order using
+// the menu's current product codes.
+func rebuildProducts(m *Menu, products []*OrderProduct, o *Order, r *ReorderReport) {
+	for _, p := range products {
+		v := m.findReplacement(p)
+		if v == nil {
+			r.Missing = append(r.Missing, p.Code)
+			continue
+		}
+		if v.Code != p.Code {
+			r.Replaced[p.Code] = v.Code
+		}
+		op := OrderProductFromItem(v)
+		if len(p.Opts) > 0 {
+			op.Opts = p.Opts
+		}
+		if len(p.SideOpts) > 0 {
+			op.SideOpts = p.SideOpts
+		}
+		if p.Qty > 0 {
+			op.Qty = p.Qty
+		}
+		o.Products = append(o.Products, op)
+	}
+}
+
+// findReplacement finds the variant that a previous order product should be
+// ordered as. Returns nil if there is nothing on the menu that matches.
+func (m *Menu) findReplacement(p *OrderProduct) *Variant {
+	if v, ok := m.Variants[p.Code]; ok {
+		return m.initVariant(v)
+	}
+	if pc, ok := m.Preconfigured[p.Code]; ok {
+		if v := pc.FindVariant(m); v != nil {
+			return v
+		}
+	}
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return nil
+	}
+	var match *Variant
+	for code, v := range m.Variants {
+		if !strings.EqualFold(strings.TrimSpace(v.Name), name) {
+			continue
+		}
+		// use the lowest code so that the same product is always picked
+		if match == nil || code < match.Code {
+			match = v
+		}
+	}
+	if match == nil {
+		return nil
+	}
+	return m.initVariant(match)
+}
@@ -0,0 +1,69 @@
+package dawg
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEasyOrder_ToOrder(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/store/4328/profile", fileHandleFunc(t, "./testdata/store.json"))
+	mux.HandleFunc("/power/store/4328/menu", fileHandleFunc(t, "./testdata/menu.json"))
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &EasyOrder{}
+	err = json.Unmarshal([]byte(`{
+		"easyOrderNickName": "usual",
+		"order": {
+			"ServiceMethod": "Carryout",
+			"StoreID": "4328",
+			"Products": [
+				{"Code": "14SCREEN", "Qty": 2, "Options": {"P": {"1/1": "1.5"}}},
+				{"Code": "OLDCODE", "name": "Medium (12\") Hand Tossed Pizza", "Qty": 1},
+				{"Code": "GONE", "name": "A Pizza That Is Gone", "Qty": 1}
+			]
+		}}`), e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &UserProfile{ID: "user", FirstName: "Jimmy", cli: c.cli}
+	o, report, err := e.ToOrder(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.StoreID != "4328" || o.ServiceMethod != Carryout || o.OrderName != "usual" {
+		t.Errorf("wrong order info: %+v", o)
+	}
+	if o.CustomerID != "user" || o.FirstName != "Jimmy" {
+		t.Error("order should be sent as the user")
+	}
+	if len(o.Products) != 2 {
+		t.Fatalf("expected 2 products, got %d", len(o.Products))
+	}
+	if p := o.Products[0]; p.Code != "14SCREEN" || p.Qty != 2 || p.Opts["P"] == nil {
+		t.Errorf("first product was not copied: %+v", p)
+	}
+	if o.Products[1].Code != "12SCREEN" {
+		t.Errorf("expected the product to be replaced by 12SCREEN, got %s", o.Products[1].Code)
+	}
+	if !report.Changed() {
+		t.Error("report should show that the order changed")
+	}
+	if report.Replaced["OLDCODE"] != "12SCREEN" {
+		t.Errorf("wrong replacements: %v", report.Replaced)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != "GONE" {
+		t.Errorf("wrong missing products: %v", report.Missing)
+	}
+
+	if _, _, err = (&EasyOrder{}).ToOrder(user); err == nil {
+		t.Error("expected an error for an order with no products")
+	}
+}
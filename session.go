@@ -0,0 +1,35 @@
+package data
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/errs"
+)
+
+// SessionKey is the database key for the signed in user's session.
+const SessionKey = "user_session"
+
+// ErrNoSession is returned when no user has signed in.
+var ErrNoSession = errors.New("not signed in")
+
+// SaveSession will store the session of a signed in user.
+func SaveSession(s *dawg.Session, db cache.Putter) error {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return db.Put(SessionKey, raw)
+}
+
+// GetSession gets the session that was saved when the user signed in.
+func GetSession(db cache.Getter) (*dawg.Session, error) {
+	raw, err := db.Get(SessionKey)
+	if raw == nil {
+		return nil, ErrNoSession
+	}
+	s := &dawg.Session{}
+	return s, errs.Pair(err, json.Unmarshal(raw, s))
+}
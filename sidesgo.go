@@ -0,0 +1,207 @@
+package dawg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SideItem is an Item that can have sides like dipping cups or dressings
+// added to it.
+type SideItem interface {
+	Item
+
+	// Sides returns a map of side codes to the number of that side.
+	Sides() map[string]int
+
+	// AddSide adds a number of sides to the item.
+	AddSide(code string, qty int) error
+
+	// RemoveSide removes a side from the item.
+	RemoveSide(code string) error
+}
+
+// Sides returns the sides that come with the product, see SideItem.
+func (p *Product) Sides() map[string]int {
+	p.sides = mergeDefaultSides(p.sides, p.DefaultSides)
+	return p.sides
+}
+
+// AddSide adds a side to the product. The side must be in the product's
+// AvailableSides.
+func (p *Product) AddSide(code string, qty int) error {
+	if !p.SideAvailable(code) {
+		return fmt.Errorf("%s is not an available side for %s", code, p.Code)
+	}
+	if err := checkSideQty(code, qty); err != nil {
+		return err
+	}
+	p.Sides()[code] = qty
+	return nil
+}
+
+// RemoveSide removes a side from the product.
+func (p *Product) RemoveSide(code string) error {
+	return removeSide(p.Sides(), code)
+}
+
+// SideAvailable returns true if the side can be added to the product.
+func (p *Product) SideAvailable(code string) bool {
+	for _, side := range strings.Split(p.AvailableSides, ",") {
+		if side != "" && side == code {
+			return true
+		}
+	}
+	return false
+}
+
+// availableSides returns the codes of the sides that can be added to the
+// product.
+func (p *Product) availableSides() []string {
+	if p.AvailableSides == "" {
+		return []string{}
+	}
+	return strings.Split(p.AvailableSides, ",")
+}
+
+// Sides returns the sides that come with the variant, see SideItem.
+func (v *Variant) Sides() map[string]int {
+	defaults, _ := v.Tags["DefaultSides"].(string)
+	v.sides = mergeDefaultSides(v.sides, defaults)
+	return v.sides
+}
+
+// AddSide adds a side to the variant. If the variant's product has been
+// found then the side must be one of the product's available sides.
+func (v *Variant) AddSide(code string, qty int) error {
+	if !v.SideAvailable(code) {
+		return fmt.Errorf("%s is not an available side for %s", code, v.Code)
+	}
+	if err := checkSideQty(code, qty); err != nil {
+		return err
+	}
+	v.Sides()[code] = qty
+	return nil
+}
+
+// RemoveSide removes a side from the variant.
+func (v *Variant) RemoveSide(code string) error {
+	return removeSide(v.Sides(), code)
+}
+
+// SideAvailable returns true if the side can be added to the variant. If the
+// variant does not have a parent product then the side cannot be checked and
+// it is assumed to be available.
+func (v *Variant) SideAvailable(code string) bool {
+	if v.product == nil {
+		return true
+	}
+	return v.product.SideAvailable(code)
+}
+
+func (v *Variant) availableSides() []string {
+	if v.product == nil {
+		return nil
+	}
+	return v.product.availableSides()
+}
+
+// Sides returns the sides that will be sent with the product.
+func (p *OrderProduct) Sides() map[string]int {
+	if p.SideOpts == nil {
+		p.SideOpts = make(map[string]int)
+	}
+	return p.SideOpts
+}
+
+// AddSide adds a side to the product. If the product was made from a menu
+// item then the side must be one of the item's available sides.
+func (p *OrderProduct) AddSide(code string, qty int) error {
+	if p.availSides != nil && !contains(p.availSides, code) {
+		return fmt.Errorf("%s is not an available side for %s", code, p.Code)
+	}
+	if err := checkSideQty(code, qty); err != nil {
+		return err
+	}
+	p.Sides()[code] = qty
+	return nil
+}
+
+// RemoveSide removes a side from the product.
+func (p *OrderProduct) RemoveSide(code string) error {
+	return removeSide(p.Sides(), code)
+}
+
+// ReadableSides gives an item's sides with the names of each side from the
+// menu.
+func ReadableSides(item SideItem, m *Menu) map[string]string {
+	var (
+		out   = map[string]string{}
+		names = m.Sides[item.Category()]
+	)
+	for code, qty := range item.Sides() {
+		name := names[code].Name
+		if name == "" {
+			name = code
+		} else {
+			name = fmt.Sprintf("%s (%s)", name, code)
+		}
+		out[name] = strconv.Itoa(qty)
+	}
+	return out
+}
+
+// sideLister is an item that knows which sides can be added to it.
+type sideLister interface {
+	availableSides() []string
+}
+
+func mergeDefaultSides(sides map[string]int, defaults string) map[string]int {
+	if sides == nil {
+		sides = make(map[string]int)
+	}
+	codes, amounts, n := splitDefaults(defaults)
+	for i := 0; i < n; i++ {
+		// if the default side is not already in the sides then add it
+		if _, ok := sides[codes[i]]; ok {
+			continue
+		}
+		if qty, err := strconv.Atoi(amounts[i]); err == nil {
+			sides[codes[i]] = qty
+		}
+	}
+	return sides
+}
+
+// removeSide sets the side's quantity to zero so that sides that come with
+// a product by default are also removed.
+func removeSide(sides map[string]int, code string) error {
+	if _, ok := sides[code]; !ok {
+		return fmt.Errorf("side %s not found", code)
+	}
+	sides[code] = 0
+	return nil
+}
+
+func checkSideQty(code string, qty int) error {
+	if qty < 0 {
+		return fmt.Errorf("cannot have %d of side %s", qty, code)
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// interface checks
+var (
+	_ SideItem = (*Product)(nil)
+	_ SideItem = (*Variant)(nil)
+	_ SideItem = (*OrderProduct)(nil)
+)
@@ -0,0 +1,71 @@
+package dawg
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSides(t *testing.T) {
+	m := menuFromFile(t)
+	v, err := m.GetVariant("W08PBNLW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if qty := v.Sides()["HOTCUP"]; qty != 1 {
+		t.Errorf("expected one default HOTCUP side, got %d", qty)
+	}
+	if err = v.AddSide("SIDRAN", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err = v.AddSide("SIDMAR", 1); err == nil {
+		t.Error("marinara is not an available side for wings")
+	}
+	if err = v.AddSide("SIDRAN", -1); err == nil {
+		t.Error("expected an error for a negative side quantity")
+	}
+
+	p := OrderProductFromItem(v)
+	if p.Sides()["SIDRAN"] != 2 || p.Sides()["HOTCUP"] != 1 {
+		t.Errorf("sides not copied to the order product: %v", p.Sides())
+	}
+	if err = p.AddSide("SIDGAR", 1); err == nil {
+		t.Error("order product should only allow the variant's available sides")
+	}
+	if err = p.RemoveSide("HOTCUP"); err != nil {
+		t.Fatal(err)
+	}
+	if err = p.RemoveSide("BBQC"); err == nil {
+		t.Error("expected an error when removing a side that was not added")
+	}
+
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := &OrderProduct{}
+	if err = json.Unmarshal(b, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Sides()["SIDRAN"] != 2 || decoded.Sides()["HOTCUP"] != 0 {
+		t.Errorf("sides were not sent in the order json: %s", b)
+	}
+
+	readable := ReadableSides(p, m)
+	if readable["Ranch (SIDRAN)"] != "2" {
+		t.Errorf("wrong readable sides: %v", readable)
+	}
+
+	prod, err := m.GetProduct("F_PARMT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod.Sides()["SIDMAR"] != 1 {
+		t.Error("product should have its default sides")
+	}
+	if err = prod.AddSide("HOTCUP", 1); err == nil {
+		t.Error("hot sauce is not an available side for bread")
+	}
+	if err = prod.RemoveSide("SIDMAR"); err != nil || prod.Sides()["SIDMAR"] != 0 {
+		t.Errorf("default side was not removed: %v", err)
+	}
+}
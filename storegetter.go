@@ -1,6 +1,9 @@
 package client
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/harrybrwn/apizza/cmd/cli"
 	"github.com/harrybrwn/apizza/cmd/internal"
 	"github.com/harrybrwn/apizza/cmd/internal/obj"
@@ -23,6 +26,7 @@ type StoreFinder interface {
 type storegetter struct {
 	getaddr   func() dawg.Address
 	getmethod func() string
+	getprefer func() string
 	dstore    *dawg.Store
 }
 
@@ -32,11 +36,25 @@ func NewStoreGetter(builder cli.Builder) StoreFinder {
 		getmethod: func() string {
 			return builder.Config().Service
 		},
+		getprefer: func() string {
+			return builder.GlobalOptions().Prefer
+		},
 		getaddr: builder.Address,
 		dstore:  nil,
 	}
 }
 
+// NewPreferredStoreGetter is the same as NewStoreGetterFunc except that the
+// store is chosen using a store preference (see ParsePreference).
+func NewPreferredStoreGetter(service, prefer func() string, addr func() dawg.Address) StoreFinder {
+	return &storegetter{
+		getmethod: service,
+		getprefer: prefer,
+		getaddr:   addr,
+		dstore:    nil,
+	}
+}
+
 // NewStoreGetterFunc creates a new store getter from two funcs
 func NewStoreGetterFunc(service func() string, addr func() dawg.Address) StoreFinder {
 	return &storegetter{
@@ -53,7 +71,11 @@ func (s *storegetter) Store() *dawg.Store {
 		if obj.AddrIsEmpty(address) {
 			errs.StopNow(errs.New(internal.ErrNoAddress), "Error", 1)
 		}
-		s.dstore, err = dawg.NearestStore(address, s.getmethod())
+		var prefer string
+		if s.getprefer != nil {
+			prefer = s.getprefer()
+		}
+		s.dstore, err = FindStore(address, s.getmethod(), prefer)
 		if err != nil {
 			errs.StopNow(err, "Store Find Error", 1) // will exit
 		}
@@ -64,3 +86,27 @@ func (s *storegetter) Store() *dawg.Store {
 func (s *storegetter) Address() dawg.Address {
 	return s.getaddr()
 }
+
+// ParsePreference converts a store preference from the command line into a
+// store ranking. The preference should be "closest", "fastest", or "best"
+// (a mix of the two) and an empty preference is the same as "closest".
+func ParsePreference(prefer string) (dawg.StoreRanking, error) {
+	switch strings.ToLower(prefer) {
+	case "", "closest":
+		return dawg.ByDistance, nil
+	case "fastest":
+		return dawg.ByWaitTime, nil
+	case "best":
+		return dawg.ByScore, nil
+	}
+	return dawg.ByDistance, fmt.Errorf("unknown store preference '%s' (use closest, fastest, or best)", prefer)
+}
+
+// FindStore finds the store for an address that matches a store preference.
+func FindStore(addr dawg.Address, service, prefer string) (*dawg.Store, error) {
+	by, err := ParsePreference(prefer)
+	if err != nil {
+		return nil, err
+	}
+	return dawg.PreferredStore(addr, service, by)
+}
@@ -1,8 +1,8 @@
 package dawg
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"net/http"
 	"sync"
@@ -33,13 +33,40 @@ const (
 // store itself. The service should be either "Carryout" or "Delivery", this will
 // determine wether the final order will be for pickup or delivery.
 func NearestStore(addr Address, service string) (*Store, error) {
-	return getNearestStore(orderClient, addr, service)
+	return getNearestStore(context.Background(), orderClient, addr, service)
+}
+
+// NearestStoreContext is the same as NearestStore but the context is used
+// for every request made.
+func NearestStoreContext(ctx context.Context, addr Address, service string) (*Store, error) {
+	return getNearestStore(ctx, orderClient, addr, service)
 }
 
 // GetNearbyStores is a way of getting all the nearby stores
-// except they will by full initialized.
+// except they will by full initialized. Stores that could not be
+// initialized are left out and an error is only returned if none of them
+// could be, see FindNearbyStores for the errors of each store.
 func GetNearbyStores(addr Address, service string) ([]*Store, error) {
-	return asyncNearbyStores(orderClient, addr, service)
+	return asyncNearbyStores(context.Background(), orderClient, addr, service)
+}
+
+// GetNearbyStoresContext is the same as GetNearbyStores but it will stop
+// initializing stores when the context is canceled.
+func GetNearbyStoresContext(ctx context.Context, addr Address, service string) ([]*Store, error) {
+	return asyncNearbyStores(ctx, orderClient, addr, service)
+}
+
+// FindNearbyStores initializes all the stores near an address and gives
+// back the stores that worked along with an error for each store that did
+// not. An error is only returned if the stores could not be found at all.
+func FindNearbyStores(addr Address, service string) (*NearbyStores, error) {
+	return nearbyStores(context.Background(), orderClient, addr, service)
+}
+
+// FindNearbyStoresContext is the same as FindNearbyStores but it will stop
+// initializing stores when the context is canceled.
+func FindNearbyStoresContext(ctx context.Context, addr Address, service string) (*NearbyStores, error) {
+	return nearbyStores(ctx, orderClient, addr, service)
 }
 
 // NewStore returns the default Store object given a store id.
@@ -51,8 +78,12 @@ func GetNearbyStores(addr Address, service string) ([]*Store, error) {
 // The addr argument should be the address to deliver to not the address of the
 // store itself.
 func NewStore(id string, service string, addr Address) (*Store, error) {
-	store := &Store{userService: service, userAddress: addr, cli: orderClient}
-	return store, InitStore(id, store)
+	return newStore(context.Background(), orderClient, id, service, addr)
+}
+
+// NewStoreContext is the same as NewStore but with a context.
+func NewStoreContext(ctx context.Context, id string, service string, addr Address) (*Store, error) {
+	return newStore(ctx, orderClient, id, service, addr)
 }
 
 // InitStore allows for the creation of arbitrary store objects. The main
@@ -65,24 +96,40 @@ func NewStore(id string, service string, addr Address) (*Store, error) {
 //	err := dawg.InitStore(id, &store)
 // This will allow all of the fields sent in the api to be viewed.
 func InitStore(id string, obj interface{}) error {
-	return initStore(orderClient, id, obj)
+	return initStore(context.Background(), orderClient, id, obj)
+}
+
+// InitStoreContext is the same as InitStore but with a context.
+func InitStoreContext(ctx context.Context, id string, obj interface{}) error {
+	return initStore(ctx, orderClient, id, obj)
 }
 
 var orderClient = &client{
-	host: orderHost,
-	Client: &http.Client{
+	host:   orderHost,
+	Client: newHTTPClient(),
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{
 		Timeout:       60 * time.Second,
 		CheckRedirect: noRedirects,
 		Transport: newRoundTripper(func(req *http.Request) error {
-			auth.SetDawgUserAgent(req.Header)
+			if req.Header.Get("User-Agent") == "" {
+				auth.SetDawgUserAgent(req.Header)
+			}
 			return nil
 		}),
-	},
+	}
 }
 
-func initStore(cli *client, id string, obj interface{}) error {
+func newStore(ctx context.Context, cli *client, id, service string, addr Address) (*Store, error) {
+	store := &Store{userService: service, userAddress: addr, cli: cli}
+	return store, initStore(ctx, cli, id, store)
+}
+
+func initStore(ctx context.Context, cli *client, id string, obj interface{}) error {
 	path := fmt.Sprintf(profileEndpoint, id)
-	b, err := cli.get(path, nil)
+	b, err := cli.get(ctx, path, nil)
 	if err != nil {
 		return err
 	}
@@ -104,6 +151,12 @@ type Store struct {
 	PaymentTypes    []string `json:"AcceptablePaymentTypes"`
 	CreditCardTypes []string `json:"AcceptableCreditCards"`
 
+	// AcceptAnonymousCards is true if the store accepts cards that are not
+	// saved to an account.
+	AcceptAnonymousCards bool `json:"AcceptAnonymousCreditCards"`
+	AcceptSavedCards     bool `json:"AcceptSavedCreditCard"`
+	AcceptGiftCards      bool
+
 	Address     string `json:"AddressDescription"`
 	PostalCode  string
 	City        string
@@ -125,6 +178,14 @@ type Store struct {
 
 	MinDeliveryOrderAmnt float64 `json:"MinimumDeliveryOrderAmount"`
 
+	// TimeZoneMinutes is the store's offset from UTC in minutes.
+	TimeZoneMinutes int
+	TimeZoneCode    string
+
+	// FutureOrderDelayInHours is how far ahead scheduled orders have to
+	// be made.
+	FutureOrderDelayInHours int
+
 	Status int
 
 	userAddress Address
@@ -135,32 +196,44 @@ type Store struct {
 
 // StoreHours is a struct that holds Dominos store hours.
 type StoreHours struct {
-	Sun, Mon, Tue, Wed, Thu, Fri, Sat []struct {
-		OpenTime  string
-		CloseTime string
-	}
+	Sun, Mon, Tue, Wed, Thu, Fri, Sat []OpenHours
+}
+
+// OpenHours is a time that a store opens and closes on one day. The times are
+// in the store's time zone using the "15:04" format. A store that closes
+// after midnight will have a CloseTime that is before its OpenTime.
+type OpenHours struct {
+	OpenTime  string
+	CloseTime string
 }
 
 // Menu returns the menu for a store object
 func (s *Store) Menu() (*Menu, error) {
+	return s.MenuContext(context.Background())
+}
+
+// MenuContext returns the menu for a store object using a context for
+// the request.
+func (s *Store) MenuContext(ctx context.Context) (*Menu, error) {
 	var err error
 	if s.menu != nil && s.menu.ID == s.ID {
 		return s.menu, nil
 	}
-	s.menu, err = newMenu(s.cli, s.ID)
+	s.menu, err = newMenu(ctx, s.client(), s.ID)
 	return s.menu, err
 }
 
 // NewOrder is a convenience function for creating an order from some of the store variables.
 func (s *Store) NewOrder() *Order {
 	return &Order{
-		LanguageCode:  DefaultLang,
+		LanguageCode:  s.client().language(),
 		ServiceMethod: s.userService,
 		StoreID:       s.ID,
 		Products:      []*OrderProduct{},
 		Address:       StreetAddrFromAddress(s.userAddress),
 		Payments:      []*orderPayment{},
-		cli:           orderClient,
+		Coupons:       []*OrderCoupon{},
+		cli:           s.client(),
 	}
 }
 
@@ -170,14 +243,22 @@ func (s *Store) MakeOrder(firstname, lastname, email string) *Order {
 		FirstName:     firstname,
 		LastName:      lastname,
 		Email:         email,
-		LanguageCode:  DefaultLang,
+		LanguageCode:  s.client().language(),
 		ServiceMethod: s.userService,
 		StoreID:       s.ID,
 		Products:      []*OrderProduct{},
 		Address:       StreetAddrFromAddress(s.userAddress),
 		Payments:      []*orderPayment{},
-		cli:           orderClient,
+		Coupons:       []*OrderCoupon{},
+		cli:           s.client(),
+	}
+}
+
+func (s *Store) client() *client {
+	if s.cli == nil {
+		return orderClient
 	}
+	return s.cli
 }
 
 // GetProduct finds the menu Product that matchs the given product code.
@@ -222,37 +303,21 @@ type StoreLocs struct {
 	Stores      []*Store    `json:"Stores"`
 }
 
-func getNearestStore(c *client, addr Address, service string) (*Store, error) {
-	if addr == nil {
-		return nil, errors.New("no address")
-	}
-	locs, err := findNearbyStores(c, addr, service)
-	if err != nil {
-		return nil, err
-	}
-
-	var store = locs.Stores[0]
-	for i := range locs.Stores {
-		if locs.Stores[i].IsOnlineNow {
-			store = locs.Stores[i]
-			break
-		}
-	}
-	store.userAddress, store.userService = addr, service
-	return store, initStore(c, store.ID, store)
+func getNearestStore(ctx context.Context, c *client, addr Address, service string) (*Store, error) {
+	return getStore(ctx, c, addr, service, ByDistance)
 }
 
-func findNearbyStores(c *client, addr Address, service string) (*StoreLocs, error) {
+func findNearbyStores(ctx context.Context, c *client, addr Address, service string) (*StoreLocs, error) {
 	if !(service == Delivery || service == Carryout) {
 		return nil, ErrBadService
 	}
 	// TODO: on the dominos website, the c param can sometimes be just the zip code
 	// and it still works.
-	resp, err := get(c, orderHost, "/power/store-locator", &Params{
+	resp, err := c.Do(c.newRequest(ctx, "GET", "/power/store-locator", &Params{
 		"s":    addr.LineOne(),
 		"c":    format("%s, %s %s", addr.City(), addr.StateCode(), addr.Zip()),
 		"type": service,
-	})
+	}, nil))
 	if err != nil {
 		return nil, err
 	}
@@ -271,73 +336,138 @@ func findNearbyStores(c *client, addr Address, service string) (*StoreLocs, erro
 	return result.StoreLocs, nil
 }
 
-func asyncNearbyStores(cli *client, addr Address, service string) ([]*Store, error) {
-	all, err := findNearbyStores(cli, addr, service)
+// maxStoreWorkers is the most store profiles that will be downloaded at once.
+const maxStoreWorkers = 4
+
+// NearbyStores is the result of looking up every store near an address. The
+// stores that could not be initialized do not fail the whole lookup, they are
+// left out of Stores and their errors are put in Errors.
+type NearbyStores struct {
+	// Stores are the fully initialized stores in the order given by the
+	// store locator.
+	Stores []*Store
+	// Errors has an error for each store that could not be initialized.
+	Errors []*StoreError
+}
+
+// StoreError is the error for a single store that could not be initialized.
+type StoreError struct {
+	ID  string
+	Err error
+}
+
+func (e *StoreError) Error() string {
+	return fmt.Sprintf("store %s: %v", e.ID, e.Err)
+}
+
+// Unwrap returns the error that caused the store to fail.
+func (e *StoreError) Unwrap() error {
+	return e.Err
+}
+
+// asyncNearbyStores returns the stores that were initialized and only fails
+// if none of them could be.
+func asyncNearbyStores(ctx context.Context, cli *client, addr Address, service string) ([]*Store, error) {
+	res, err := nearbyStores(ctx, cli, addr, service)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Stores) == 0 && len(res.Errors) > 0 {
+		return nil, res.Errors[0]
+	}
+	return res.Stores, nil
+}
+
+func nearbyStores(ctx context.Context, cli *client, addr Address, service string) (*NearbyStores, error) {
+	all, err := findNearbyStores(ctx, cli, addr, service)
 	if err != nil {
-		return nil, fmt.Errorf("findNearbyStores: %v", err)
+		return nil, fmt.Errorf("findNearbyStores: %w", err)
 	}
 
 	var (
 		nStores = len(all.Stores)
-		stores  = make([]*Store, nStores) // return value
-		store   *Store
-		pair    maybeStore
+		workers = maxStoreWorkers
 		builder = storebuilder{
-			WaitGroup: sync.WaitGroup{},
-			stores:    make(chan maybeStore),
+			jobs:    make(chan int),
+			results: make([]maybeStore, nStores),
 		}
 	)
-	builder.Add(nStores)
+	if nStores < workers {
+		workers = nStores
+	}
+	builder.Add(workers)
+	for w := 0; w < workers; w++ {
+		go builder.work(ctx, cli, all.Stores)
+	}
 
-	go func() {
-		defer close(builder.stores)
-		for i, store := range all.Stores {
-			go builder.initStore(cli, store.ID, i)
+feed:
+	for i := range all.Stores {
+		select {
+		case builder.jobs <- i:
+		case <-ctx.Done():
+			break feed // stop starting new requests once canceled
 		}
+	}
+	// the workers stop once the jobs run out so none of them are left behind
+	close(builder.jobs)
+	builder.Wait()
 
-		builder.Wait()
-	}()
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	for pair = range builder.stores {
+	res := &NearbyStores{Stores: make([]*Store, 0, nStores)}
+	for i, pair := range builder.results {
 		if pair.err != nil {
-			return nil, pair.err
+			res.Errors = append(res.Errors, &StoreError{ID: all.Stores[i].ID, Err: pair.err})
+			continue
 		}
-		store = pair.store
+		store := pair.store
+		// store profiles do not have the distance from the address
+		store.MinDistance = all.Stores[i].MinDistance
+		store.MaxDistance = all.Stores[i].MaxDistance
 		store.userAddress = addr
 		store.userService = service
 		store.cli = cli
-
-		stores[pair.index] = store
+		res.Stores = append(res.Stores, store)
 	}
-
-	return stores, err
+	return res, nil
 }
 
+// storebuilder is a pool of workers that initialize stores. Every worker
+// writes to its own index in results so they never have to wait on each
+// other.
 type storebuilder struct {
 	sync.WaitGroup
-	stores chan maybeStore
+	jobs    chan int
+	results []maybeStore
 }
 
 type maybeStore struct {
 	store *Store
-	index int
 	err   error
 }
 
-func (sb *storebuilder) initStore(cli *client, id string, index int) {
+func (sb *storebuilder) work(ctx context.Context, cli *client, stores []*Store) {
 	defer sb.Done()
+	for i := range sb.jobs {
+		sb.initStore(ctx, cli, stores[i].ID, i)
+	}
+}
+
+func (sb *storebuilder) initStore(ctx context.Context, cli *client, id string, index int) {
 	path := fmt.Sprintf(profileEndpoint, id)
 	store := &Store{}
 
-	b, err := cli.get(path, nil)
+	b, err := cli.get(ctx, path, nil)
 	if err != nil {
-		sb.stores <- maybeStore{store: nil, err: err, index: -1}
+		sb.results[index] = maybeStore{err: err}
+		return
 	}
-
 	err = errpair(json.Unmarshal(b, store), dominosErr(b))
 	if err != nil {
-		sb.stores <- maybeStore{store: nil, err: err, index: -1}
+		sb.results[index] = maybeStore{err: err}
+		return
 	}
-
-	sb.stores <- maybeStore{store: store, err: nil, index: index}
+	sb.results[index] = maybeStore{store: store}
 }
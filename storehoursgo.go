@@ -0,0 +1,141 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const week = 7 * 24 * time.Hour
+
+// hoursRange is a span of time that a store is open measured from midnight
+// at the beginning of Sunday.
+type hoursRange struct {
+	open, close time.Duration
+}
+
+func (r hoursRange) contains(d time.Duration) bool {
+	// a range that ends after midnight on Saturday wraps around the week
+	return (r.open <= d && d < r.close) || (r.open <= d+week && d+week < r.close)
+}
+
+// ranges returns the times that the store is open during the week. Hours
+// that cannot be parsed are left out.
+func (h *StoreHours) ranges() []hoursRange {
+	days := [...][]OpenHours{h.Sun, h.Mon, h.Tue, h.Wed, h.Thu, h.Fri, h.Sat}
+	ranges := make([]hoursRange, 0, len(days))
+	for i, day := range days {
+		start := time.Duration(i) * 24 * time.Hour
+		for _, hours := range day {
+			opens, err := parseClock(hours.OpenTime)
+			if err != nil {
+				continue
+			}
+			closes, err := parseClock(hours.CloseTime)
+			if err != nil {
+				continue
+			}
+			if closes <= opens {
+				// closes after midnight
+				closes += 24 * time.Hour
+			}
+			ranges = append(ranges, hoursRange{open: start + opens, close: start + closes})
+		}
+	}
+	return ranges
+}
+
+// parseClock parses a time of day like "22:59" into the time since midnight.
+// Dominos uses "23:59" to mean that a store closes at midnight.
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, err
+	}
+	d := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if d == 23*time.Hour+59*time.Minute {
+		d = 24 * time.Hour
+	}
+	return d, nil
+}
+
+// sinceSunday returns the time since the beginning of the week.
+func sinceSunday(t time.Time) time.Duration {
+	return time.Duration(t.Weekday())*24*time.Hour +
+		time.Duration(t.Hour())*time.Hour +
+		time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+}
+
+// hours returns the hours for a service method, or the store's hours if the
+// store does not list hours for that service.
+func (s *Store) hours(service string) *StoreHours {
+	if h, ok := s.ServiceHours[service]; ok {
+		return &h
+	}
+	return &s.Hours
+}
+
+// location returns the store's time zone.
+func (s *Store) location(t time.Time) *time.Location {
+	if s.TimeZoneCode == "" && s.TimeZoneMinutes == 0 {
+		return t.Location()
+	}
+	return time.FixedZone(s.TimeZoneCode, s.TimeZoneMinutes*60)
+}
+
+// Location returns the store's time zone. The local time zone is used if the
+// store did not give one.
+func (s *Store) Location() *time.Location {
+	return s.location(time.Now())
+}
+
+// IsOpenAt will tell if the store is open for a service method at some
+// time. The store's regular hours are used if it does not have hours for
+// the service.
+func (s *Store) IsOpenAt(service string, t time.Time) bool {
+	d := sinceSunday(t.In(s.location(t)))
+	for _, r := range s.hours(service).ranges() {
+		if r.contains(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpening returns the next time that the store will be open for a
+// service method. If the store is already open at the time given then that
+// same time is returned.
+func (s *Store) NextOpening(service string, t time.Time) (time.Time, error) {
+	if s.IsOpenAt(service, t) {
+		return t, nil
+	}
+	d := sinceSunday(t.In(s.location(t)))
+	var next time.Duration = -1
+	for _, r := range s.hours(service).ranges() {
+		wait := r.open - d
+		if wait < 0 {
+			wait += week
+		}
+		if next < 0 || wait < next {
+			next = wait
+		}
+	}
+	if next < 0 {
+		return time.Time{}, errors.New("store has no hours for " + service)
+	}
+	return t.Add(next).Truncate(time.Minute), nil
+}
+
+// CheckOpen returns an error if the store is closed for a service method at
+// some time. Stores that did not send their hours are not checked so that
+// dominos can decide when the order is sent.
+func (s *Store) CheckOpen(service string, t time.Time) error {
+	next, err := s.NextOpening(service, t)
+	if err != nil || next.Equal(t) {
+		return nil
+	}
+	return fmt.Errorf("the store is closed for %s until %s",
+		strings.ToLower(service), next.In(s.location(next)).Format("Mon Jan 2 3:04pm"))
+}
@@ -0,0 +1,79 @@
+package dawg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStore_IsOpenAt(t *testing.T) {
+	store := storeFromFile(t)
+	zone := time.FixedZone("", -4*60*60)
+	friday := time.Date(2020, time.April, 10, 23, 45, 0, 0, zone)
+	if !store.IsOpenAt(Delivery, friday) {
+		t.Error("delivery should be open at 11:45pm on friday")
+	}
+	if store.IsOpenAt(Carryout, friday) {
+		t.Error("carryout should be closed at 11:45pm on friday")
+	}
+	// the same time in another time zone
+	if store.IsOpenAt(Carryout, friday.UTC()) {
+		t.Error("store's time zone should be used")
+	}
+	next, err := store.NextOpening(Carryout, friday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2020, time.April, 11, 10, 0, 0, 0, zone); !next.Equal(exp) {
+		t.Errorf("wrong next opening: got %v, want %v", next, exp)
+	}
+	if next, err = store.NextOpening(Delivery, friday); err != nil || !next.Equal(friday) {
+		t.Error("next opening of an open store should be the time given")
+	}
+
+	late := &Store{Hours: StoreHours{
+		Fri: []OpenHours{{OpenTime: "18:00", CloseTime: "02:00"}},
+		Sat: []OpenHours{{OpenTime: "20:00", CloseTime: "01:00"}},
+	}}
+	cases := []struct {
+		t    time.Time
+		open bool
+	}{
+		{time.Date(2020, time.April, 10, 19, 0, 0, 0, time.UTC), true}, // friday
+		{time.Date(2020, time.April, 11, 1, 30, 0, 0, time.UTC), true}, // saturday morning
+		{time.Date(2020, time.April, 11, 3, 0, 0, 0, time.UTC), false}, // saturday morning
+		{time.Date(2020, time.April, 12, 0, 30, 0, 0, time.UTC), true}, // sunday morning
+		{time.Date(2020, time.April, 12, 1, 0, 0, 0, time.UTC), false}, // sunday morning
+		{time.Date(2020, time.April, 9, 19, 0, 0, 0, time.UTC), false}, // thursday
+	}
+	for _, tc := range cases {
+		if late.IsOpenAt(Delivery, tc.t) != tc.open {
+			t.Errorf("%v: expected open to be %v", tc.t, tc.open)
+		}
+	}
+	next, err = late.NextOpening(Delivery, time.Date(2020, time.April, 12, 1, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := time.Date(2020, time.April, 17, 18, 0, 0, 0, time.UTC); !next.Equal(exp) {
+		t.Errorf("wrong next opening: got %v, want %v", next, exp)
+	}
+	if _, err = (&Store{}).NextOpening(Delivery, time.Now()); err == nil {
+		t.Error("expected an error for a store with no hours")
+	}
+}
+
+func TestStore_CheckOpen(t *testing.T) {
+	store := &Store{ServiceHours: map[string]StoreHours{
+		Carryout: {Mon: []OpenHours{{OpenTime: "10:00", CloseTime: "22:00"}}},
+	}}
+	monday := time.Date(2020, time.April, 6, 12, 0, 0, 0, time.Local)
+	if err := store.CheckOpen(Carryout, monday); err != nil {
+		t.Error(err)
+	}
+	if err := store.CheckOpen(Carryout, monday.Add(12*time.Hour)); err == nil {
+		t.Error("expected an error when the store is closed")
+	}
+	if err := (&Store{}).CheckOpen(Carryout, monday); err != nil {
+		t.Error("stores without hours should not be checked")
+	}
+}
@@ -0,0 +1,143 @@
+package dawg
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+)
+
+// StoreRanking is a way of choosing between stores, see SortStores.
+type StoreRanking int
+
+const (
+	// ByDistance ranks the closest stores first.
+	ByDistance StoreRanking = iota
+	// ByWaitTime ranks the stores with the shortest estimated wait first.
+	ByWaitTime
+	// ByScore ranks stores by a mix of distance and wait time, see Store.Score.
+	ByScore
+)
+
+// minutesPerMile is how many minutes of waiting one mile of distance is
+// worth when scoring stores.
+const minutesPerMile = 3.0
+
+// SortStores sorts a list of stores by a ranking. Stores that are not online
+// are always put last and stores that rank the same keep their order.
+//
+// Distances and wait times are found using the address and service method
+// that the stores were found with.
+func SortStores(stores []*Store, by StoreRanking) {
+	key := func(s *Store) float64 {
+		switch by {
+		case ByWaitTime:
+			return s.avgWait()
+		case ByScore:
+			return s.Score()
+		default:
+			return s.Distance()
+		}
+	}
+	sort.SliceStable(stores, func(i, j int) bool {
+		if stores[i].IsOnlineNow != stores[j].IsOnlineNow {
+			return stores[i].IsOnlineNow
+		}
+		return key(stores[i]) < key(stores[j])
+	})
+}
+
+// PreferredStore finds the store near an address that ranks the highest.
+// NearestStore is the same as PreferredStore with ByDistance.
+func PreferredStore(addr Address, service string, by StoreRanking) (*Store, error) {
+	return getStore(context.Background(), orderClient, addr, service, by)
+}
+
+// PreferredStoreContext is the same as PreferredStore but with a context.
+func PreferredStoreContext(ctx context.Context, addr Address, service string, by StoreRanking) (*Store, error) {
+	return getStore(ctx, orderClient, addr, service, by)
+}
+
+func getStore(ctx context.Context, c *client, addr Address, service string, by StoreRanking) (*Store, error) {
+	if addr == nil {
+		return nil, errors.New("no address")
+	}
+	locs, err := findNearbyStores(ctx, c, addr, service)
+	if err != nil {
+		return nil, err
+	}
+	if len(locs.Stores) == 0 {
+		return nil, errors.New("no stores found near the address")
+	}
+	for _, s := range locs.Stores {
+		s.userAddress, s.userService = addr, service
+	}
+	SortStores(locs.Stores, by)
+	store := locs.Stores[0]
+	return store, initStore(ctx, c, store.ID, store)
+}
+
+// Coordinates returns the latitude and longitude of the store. The last
+// return value is false if the store does not have coordinates.
+func (s *Store) Coordinates() (lat, long float64, ok bool) {
+	lat, err := strconv.ParseFloat(s.StoreCoords["StoreLatitude"], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	long, err = strconv.ParseFloat(s.StoreCoords["StoreLongitude"], 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return lat, long, true
+}
+
+// GeoAddress is an address that knows its latitude and longitude.
+type GeoAddress interface {
+	Address
+	LatLong() (lat, long float64, ok bool)
+}
+
+// Distance returns the distance in miles between the store and the address
+// that it was found with. If the address has coordinates then the straight
+// line distance is used, otherwise the distance from the store locator is
+// used.
+func (s *Store) Distance() float64 {
+	if a, ok := s.userAddress.(GeoAddress); ok {
+		alat, along, ok := a.LatLong()
+		slat, slong, sok := s.Coordinates()
+		if ok && sok {
+			return haversine(alat, along, slat, slong)
+		}
+	}
+	return s.MinDistance
+}
+
+// Score gives a store a score based on its distance and estimated wait
+// time, lower is better. One mile of distance is worth three minutes of
+// waiting.
+func (s *Store) Score() float64 {
+	return s.avgWait() + s.Distance()*minutesPerMile
+}
+
+// avgWait returns the average estimated wait time in minutes. Stores that
+// do not give a wait time are ranked last.
+func (s *Store) avgWait() float64 {
+	min, max := s.WaitTime()
+	if min == 0 && max == 0 {
+		return math.Inf(1)
+	}
+	return float64(min+max) / 2
+}
+
+const earthRadiusMiles = 3958.8
+
+// haversine returns the distance in miles between two coordinates.
+func haversine(lat1, long1, lat2, long2 float64) float64 {
+	rad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dlat := rad(lat2 - lat1)
+	dlong := rad(long2 - long1)
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Sin(dlong/2)*math.Sin(dlong/2)
+	return 2 * earthRadiusMiles * math.Asin(math.Sqrt(a))
+}
@@ -0,0 +1,85 @@
+package dawg
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"testing"
+)
+
+func storeLocsFromFile(t *testing.T) *StoreLocs {
+	b, err := ioutil.ReadFile("./testdata/store-locator.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	locs := &StoreLocs{}
+	if err = json.Unmarshal(b, locs); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range locs.Stores {
+		s.userAddress, s.userService = testAddress(), Delivery
+	}
+	return locs
+}
+
+func TestSortStores(t *testing.T) {
+	locs := storeLocsFromFile(t)
+	for _, tc := range []struct {
+		by    StoreRanking
+		first string
+	}{
+		{ByDistance, "4344"},
+		{ByWaitTime, "4329"},
+		{ByScore, "4329"},
+	} {
+		SortStores(locs.Stores, tc.by)
+		if id := locs.Stores[0].ID; id != tc.first {
+			t.Errorf("ranking %d: expected %s first, got %s", tc.by, tc.first, id)
+		}
+		last := locs.Stores[len(locs.Stores)-1]
+		if last.IsOnlineNow {
+			t.Error("stores that are not online should be last")
+		}
+	}
+
+	// a store at the same coordinates as the address
+	store := locs.Stores[0]
+	lat, long, ok := store.Coordinates()
+	if !ok {
+		t.Fatal("store should have coordinates")
+	}
+	store.userAddress = &UserAddress{Coordinates: map[string]float32{
+		"Latitude": float32(lat), "Longitude": float32(long)}}
+	if d := store.Distance(); d > 0.01 {
+		t.Errorf("expected no distance, got %f", d)
+	}
+	// Washington DC to New York City
+	if d := haversine(38.9072, -77.0369, 40.7128, -74.0060); math.Abs(d-203) > 2 {
+		t.Errorf("wrong distance: %f", d)
+	}
+}
+
+func TestPreferredStore(t *testing.T) {
+	cli, mux, server := testServer()
+	defer server.Close()
+	defer swapClientWith(cli)()
+	mux.HandleFunc("/power/store-locator", storeLocatorHandlerFunc(t))
+	var requested string
+	mux.HandleFunc("/power/store/", func(w http.ResponseWriter, r *http.Request) {
+		requested = r.URL.Path
+		fileHandleFunc(t, "./testdata/store.json")(w, r)
+	})
+	if _, err := PreferredStore(testAddress(), Delivery, ByWaitTime); err != nil {
+		t.Fatal(err)
+	}
+	if requested != "/power/store/4329/profile" {
+		t.Errorf("the fastest store was not used: %s", requested)
+	}
+	if _, err := NearestStore(testAddress(), Delivery); err != nil {
+		t.Fatal(err)
+	}
+	if requested != "/power/store/4344/profile" {
+		t.Errorf("the closest online store was not used: %s", requested)
+	}
+}
@@ -1,6 +1,7 @@
 package dawg
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -57,7 +58,7 @@ func TestNearestStore_Err(t *testing.T) {
 func TestGetAllNearbyStores(t *testing.T) {
 	tests.InitHelpers(t)
 	addr := testAddress()
-	validation, err := findNearbyStores(orderClient, addr, "Delivery")
+	validation, err := findNearbyStores(context.Background(), orderClient, addr, "Delivery")
 	if err != nil {
 		t.Error(err)
 	}
@@ -129,7 +130,7 @@ func TestInitStore_Err(t *testing.T) {
 	ids := []string{"", "0000", "999999999999", "-7765"}
 	for _, id := range ids {
 		s := new(Store)
-		err := initStore(orderClient, id, s)
+		err := initStore(context.Background(), orderClient, id, s)
 		if err == nil {
 			t.Error("expected error from a ridiculous store id")
 		}
@@ -139,7 +140,7 @@ func TestInitStore_Err(t *testing.T) {
 func TestGetNearestStore(t *testing.T) {
 	a := testAddress()
 	for _, service := range []string{Delivery, Carryout} {
-		s, err := getNearestStore(orderClient, a, service)
+		s, err := getNearestStore(context.Background(), orderClient, a, service)
 		if err != nil {
 			t.Error(err)
 		}
@@ -4,11 +4,16 @@ import (
 	"io"
 	"text/template"
 
+	"github.com/harrybrwn/apizza/dawg"
 	"github.com/harrybrwn/apizza/pkg/errs"
 )
 
+var tmplFuncs = template.FuncMap{
+	"money": func(f float64) string { return dawg.DefaultMarket().FormatPrice(f) },
+}
+
 func tmpl(w io.Writer, tmplt string, a interface{}) (err error) {
-	t := template.New("apizza")
+	t := template.New("apizza").Funcs(tmplFuncs)
 	t, err = t.Parse(tmplt)
 	return errs.Pair(err, t.Execute(w, a))
 }
@@ -21,15 +26,39 @@ var defaultOrderTmpl = `{{ .OrderName }}
       {{$keycol}}code{{$endcol}}:     {{.Code}}
       {{$keycol}}options{{$endcol}}:{{ range $k, $v := .ReadableOptions }}
          {{$keycol}}{{$k}}{{$endcol}}: {{$v}}{{else}}None{{end}}
+{{- if .Sides }}
+      {{$keycol}}sides{{$endcol}}:{{ range $k, $v := .Sides }}
+         {{$keycol}}{{$k}}{{$endcol}}: {{$v}}{{end}}{{end}}
       {{$keycol}}quantity{{$endcol}}: {{.Qty}}{{end}}
   {{.KeyColor}}storeID{{.EndColor}}: {{.StoreID}}
   {{.KeyColor}}method{{.EndColor}}:  {{.ServiceMethod}}
   {{.KeyColor}}address{{.EndColor}}: {{.Addr -}}
+{{ if .Coupons }}
+  {{.KeyColor}}coupons{{.EndColor}}: {{ range $i, $c := .Coupons }}{{ if $i }}, {{end}}{{ $c.Code }}{{end}}
+{{- end -}}
 {{ if .Price }}
-  {{.KeyColor}}price{{.EndColor}}:   ${{ .Price -}}
+  {{.KeyColor}}price{{.EndColor}}:   {{ money .Price -}}
 {{else}}{{end}}
 `
 
+var receiptTmpl = `  {{.KeyColor}}receipt{{.EndColor}}:
+    food and beverage: {{ money .Breakdown.FoodAndBeverage }}
+{{- if .Breakdown.DeliveryFee }}
+    delivery fee:      {{ money .Breakdown.DeliveryFee }}{{end}}
+{{- if .Breakdown.Surcharge }}
+    surcharge:         {{ money .Breakdown.Surcharge }}{{end}}
+{{- if .Breakdown.Bottle }}
+    bottle deposit:    {{ money .Breakdown.Bottle }}{{end}}
+{{- if .Breakdown.Adjustment }}
+    adjustment:        {{ money .Breakdown.Adjustment }}{{end}}
+{{- if .Breakdown.Savings }}
+    savings:          -{{ money .Breakdown.Savings }}{{end}}
+    tax:               {{ money .Breakdown.Tax }}
+    {{.KeyColor}}total{{.EndColor}}:             {{ money .Amounts.Customer }}
+{{- if .EstimatedWaitMinutes }}
+  {{.KeyColor}}estimated wait{{.EndColor}}: {{ .EstimatedWaitMinutes }} minutes{{end}}
+`
+
 var cartOrderTmpl = `  {{ .OrderName }} - {{ range .Products }} {{.Code}}, {{end}}
 `
 
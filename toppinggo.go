@@ -0,0 +1,144 @@
+package dawg
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToppingError is returned when a topping cannot be added to an item.
+type ToppingError struct {
+	// Topping is the topping code.
+	Topping string
+	// Item is the code of the item that the topping was being added to.
+	Item string
+	// Err is the reason that the topping could not be added.
+	Err error
+}
+
+func (e *ToppingError) Error() string {
+	return fmt.Sprintf("cannot add topping '%s' to %s: %v", e.Topping, e.Item, e.Err)
+}
+
+// Unwrap returns the reason that the topping could not be added.
+func (e *ToppingError) Unwrap() error {
+	return e.Err
+}
+
+var (
+	// ErrToppingNotFound is used when a topping is not on the menu.
+	ErrToppingNotFound = errors.New("topping not found on the menu")
+
+	// ErrToppingUnavailable is used when a topping is on the menu but cannot
+	// be put on a specific product.
+	ErrToppingUnavailable = errors.New("topping is not available for this product")
+
+	// ErrToppingWholeOnly is used when a topping that must cover the whole
+	// item is put on one side.
+	ErrToppingWholeOnly = errors.New("topping can only cover the whole item")
+)
+
+// ValidateTopping checks that a topping can be added to an item using the
+// rules on the menu. The topping code must exist for the item's product type,
+// be one of the product's available toppings, and the amount and coverage
+// must be allowed for that product. Errors returned are always a
+// *ToppingError.
+func (m *Menu) ValidateTopping(item Item, code, cover, amount string) error {
+	toppingErr := func(err error) error {
+		return &ToppingError{Topping: code, Item: item.ItemCode(), Err: err}
+	}
+
+	var (
+		prod     = m.productOf(item)
+		category string
+	)
+	if prod != nil {
+		category = prod.ProductType
+	} else {
+		category = item.Category()
+	}
+	toppings, ok := m.Toppings[category]
+	if !ok {
+		return toppingErr(fmt.Errorf("no toppings on the menu for '%s' items", category))
+	}
+	top, ok := toppings[code]
+	if !ok {
+		return toppingErr(ErrToppingNotFound)
+	}
+
+	qtys := productOptQtys[category]
+	if prod != nil {
+		qtys = prod.optionQtys()
+		avail, restricted := availableToppings(prod.AvailableToppings)
+		if restricted {
+			toppingQtys, ok := avail[code]
+			if !ok {
+				return toppingErr(ErrToppingUnavailable)
+			}
+			if toppingQtys != nil {
+				qtys = toppingQtys
+			}
+		}
+	}
+
+	if cover != ToppingFull {
+		if whole, _ := top.Tags["WholeOnly"].(bool); whole {
+			return toppingErr(ErrToppingWholeOnly)
+		}
+		if prod != nil && prod.Tags["PartCount"] == nil {
+			return toppingErr(fmt.Errorf("%s items cannot have toppings on one side", category))
+		}
+	}
+	if _, err := makeTopping(cover, amount, qtys); err != nil {
+		return toppingErr(err)
+	}
+	return nil
+}
+
+// productOf finds the menu product for an item.
+func (m *Menu) productOf(item Item) *Product {
+	switch it := item.(type) {
+	case *Product:
+		return it
+	case *Variant:
+		return it.FindProduct(m)
+	case *PreConfiguredProduct:
+		if v := it.FindVariant(m); v != nil {
+			return v.product
+		}
+		return nil
+	}
+	code := item.ItemCode()
+	if v, ok := m.Variants[code]; ok {
+		return m.initVariant(v).product
+	}
+	if p, ok := m.Products[code]; ok {
+		return p
+	}
+	if pc, ok := m.Preconfigured[code]; ok {
+		if v := pc.FindVariant(m); v != nil {
+			return v.product
+		}
+	}
+	return nil
+}
+
+// availableToppings parses a product's AvailableToppings string. Each topping
+// is mapped to the amounts allowed for it or nil if the product's default
+// amounts are allowed. The second return value is false if there are no
+// toppings listed, in which case any topping of the product type is allowed.
+func availableToppings(s string) (map[string][]string, bool) {
+	if s == "" {
+		return nil, false
+	}
+	avail := make(map[string][]string)
+	for _, t := range strings.Split(s, ",") {
+		parts := strings.SplitN(t, "=", 2)
+		if len(parts) == 2 {
+			avail[parts[0]] = strings.Split(parts[1], ":")
+		} else {
+			avail[parts[0]] = nil
+		}
+	}
+	return avail, true
+}
@@ -0,0 +1,71 @@
+package dawg
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateTopping(t *testing.T) {
+	m := menuFromFile(t)
+	pizza, err := m.GetVariant("14SCREEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wings, err := m.GetVariant("W08PBNLW")
+	if err != nil {
+		t.Fatal(err)
+	}
+	orderPizza := &OrderProduct{ItemCommon: ItemCommon{Code: "14SCREEN"}}
+
+	for _, tc := range []struct {
+		item                Item
+		code, cover, amount string
+		err                 error
+		wantErr             bool
+	}{
+		{item: pizza, code: "P", cover: ToppingFull, amount: "1.0"},
+		{item: pizza, code: "P", cover: ToppingLeft, amount: "1.5"},
+		{item: orderPizza, code: "K", cover: ToppingRight, amount: "2.0"},
+		{item: wings, code: "K", cover: ToppingFull, amount: "3"},
+		{item: pizza, code: "ZZ", cover: ToppingFull, amount: "1.0", err: ErrToppingNotFound},
+		{item: pizza, code: "H", cover: ToppingFull, amount: "1.0", err: ErrToppingUnavailable},
+		{item: orderPizza, code: "X", cover: ToppingLeft, amount: "1.0", err: ErrToppingWholeOnly},
+		{item: pizza, code: "X", cover: ToppingFull, amount: "2.0", wantErr: true},
+		{item: pizza, code: "P", cover: ToppingFull, amount: "3.0", wantErr: true},
+		{item: pizza, code: "P", cover: "3/2", amount: "1.0", wantErr: true},
+		{item: wings, code: "K", cover: ToppingLeft, amount: "1.0", wantErr: true},
+		{item: &OrderProduct{ItemCommon: ItemCommon{Code: "nope"}}, code: "P", cover: ToppingFull, amount: "1", wantErr: true},
+	} {
+		err := m.ValidateTopping(tc.item, tc.code, tc.cover, tc.amount)
+		if tc.err == nil && !tc.wantErr {
+			if err != nil {
+				t.Errorf("%s on %s: unexpected error: %v", tc.code, tc.item.ItemCode(), err)
+			}
+			continue
+		}
+		if err == nil {
+			t.Errorf("%s on %s: expected an error", tc.code, tc.item.ItemCode())
+			continue
+		}
+		var topErr *ToppingError
+		if !errors.As(err, &topErr) || topErr.Topping != tc.code {
+			t.Errorf("expected a *ToppingError for %s, got %T", tc.code, err)
+		}
+		if tc.err != nil && !errors.Is(err, tc.err) {
+			t.Errorf("%s on %s: got %v, want %v", tc.code, tc.item.ItemCode(), err, tc.err)
+		}
+	}
+}
+
+func TestOrderProduct_AddTopping(t *testing.T) {
+	p := &OrderProduct{ItemCommon: ItemCommon{Code: "14SCREEN"}, pType: "Pizza"}
+	if err := p.AddTopping("P", ToppingFull, "1.5"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddTopping("P", ToppingFull, "5"); err == nil {
+		t.Error("expected an error for an amount that pizzas do not allow")
+	}
+	if err := p.AddTopping("P", "left", "1"); err == nil {
+		t.Error("expected an error for a bad topping coverage")
+	}
+}
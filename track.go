@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/cli"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/cache"
+	"github.com/harrybrwn/apizza/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// NewTrackCmd creates a new track command.
+func NewTrackCmd(b cli.Builder) cli.CliCommand {
+	c := &trackCmd{interval: dawg.DefaultTrackInterval, newTracker: dawg.NewTracker}
+	c.CliCommand = b.Build("track", "Track the status of an order that has been placed.", c)
+	c.db = b.DB()
+	c.Cmd().Long = `The track command follows the most recently placed order in the dominos
+order tracker until it has been delivered or picked up.
+
+Use --phone to track the latest order for a different phone number and --id
+to track a specific order.`
+
+	flags := c.Cmd().Flags()
+	flags.StringVar(&c.phone, "phone", "", "track the latest order placed with a phone number")
+	flags.StringVar(&c.id, "id", "", "track an order by its order id or pulse id")
+	flags.DurationVar(&c.interval, "interval", c.interval, "time between tracker updates")
+	flags.BoolVar(&c.once, "once", false, "print the current status and exit")
+	return c
+}
+
+// `apizza track`
+type trackCmd struct {
+	cli.CliCommand
+	db *cache.DataBase
+
+	phone    string
+	id       string
+	interval time.Duration
+	once     bool
+
+	newTracker func(phone, orderID string) *dawg.Tracker
+}
+
+func (c *trackCmd) Run(cmd *cobra.Command, args []string) error {
+	var tracker *dawg.Tracker
+	if c.phone != "" || c.id != "" {
+		tracker = c.newTracker(eitherOr(c.phone, config.GetString("phone")), c.id)
+	} else {
+		placed, err := data.GetLastOrder(c.db)
+		if err == data.ErrNoPlacedOrder {
+			return errors.New("no orders have been placed (see --phone or --id)")
+		} else if err != nil {
+			return err
+		}
+		tracker = c.newTracker(placed.Phone, placed.TrackingID)
+		if placed.Name != "" {
+			c.Printf("tracking order '%s' placed at %s\n", placed.Name, placed.Placed.Format(time.Kitchen))
+		}
+	}
+	tracker.Interval = c.interval
+
+	if c.once {
+		status, err := tracker.Status()
+		if err != nil {
+			return err
+		}
+		printStatus(c.Output(), status)
+		return nil
+	}
+	return followOrder(context.Background(), tracker, c.Output())
+}
+
+// followOrder prints every stage of an order until it is complete.
+func followOrder(ctx context.Context, t *dawg.Tracker, w io.Writer) error {
+	return t.Follow(ctx, func(s *dawg.OrderStatus) error {
+		printStatus(w, s)
+		return nil
+	})
+}
+
+func printStatus(w io.Writer, s *dawg.OrderStatus) {
+	stamp := time.Now().Format(time.Kitchen)
+	switch s.Stage {
+	case dawg.StageOutForDelivery:
+		if s.DriverName != "" {
+			fmt.Fprintf(w, "[%s] %s with %s\n", stamp, s.Stage, s.DriverName)
+			return
+		}
+	case dawg.StageUnknown:
+		fmt.Fprintf(w, "[%s] %s\n", stamp, s.Status)
+		return
+	}
+	fmt.Fprintf(w, "[%s] %s\n", stamp, s.Stage)
+}
@@ -0,0 +1,146 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harrybrwn/apizza/cmd/internal/cmdtest"
+	"github.com/harrybrwn/apizza/cmd/internal/data"
+	"github.com/harrybrwn/apizza/dawg"
+	"github.com/harrybrwn/apizza/pkg/tests"
+)
+
+// trackerStages strips the times from the output of printStatus.
+func trackerStages(out string) []string {
+	var stages []string
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		if i := strings.Index(line, "] "); strings.HasPrefix(line, "[") && i > 0 {
+			stages = append(stages, line[i+2:])
+		}
+	}
+	return stages
+}
+
+func TestTrackCmd(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	statuses := []string{"Oven", "Out the Door", "Complete"}
+	var calls int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if phone := r.URL.Query().Get("phonenumber"); phone != "5555555555" {
+			t.Errorf("wrong phone number %q", phone)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"OrderID": "1234", "OrderStatus": "Makeline"}]`)
+	})
+	mux.HandleFunc("/orders/abc-def", func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[calls]
+		if calls < len(statuses)-1 {
+			calls++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&dawg.OrderStatus{OrderID: "1234", PulseOrderGUID: "abc-def", Status: status})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c, err := dawg.NewClient(dawg.WithTrackerURL(srv.URL + "/orders"))
+	tests.Check(err)
+
+	cmd := NewTrackCmd(r).(*trackCmd)
+	cmd.newTracker = c.NewTracker
+	tests.Exp(cmd.Run(cmd.Cmd(), []string{}), "expected an error when no order has been placed")
+
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--phone=555-555-5555", "--once"}))
+	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	if stages := trackerStages(r.Out.String()); len(stages) != 1 || stages[0] != dawg.StagePrep.String() {
+		t.Errorf("wrong status for --phone: %q", r.Out.String())
+	}
+
+	r.Out.Reset()
+	cmd.phone = ""
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--id=abc-def"}))
+	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	if stages := trackerStages(r.Out.String()); len(stages) != 1 || stages[0] != dawg.StageBake.String() {
+		t.Errorf("wrong status for --id --once: %q", r.Out.String())
+	}
+
+	r.Out.Reset()
+	calls = 0
+	cmd.id, cmd.once = "", false
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--interval=1ms"}))
+	raw, err := json.Marshal(&data.PlacedOrder{Name: "pizza", TrackingID: "abc-def", Placed: time.Now()})
+	tests.Check(err)
+	tests.Check(r.DataBase.Put(data.LastOrderKey, raw))
+	tests.Check(cmd.Run(cmd.Cmd(), []string{}))
+	if !strings.HasPrefix(r.Out.String(), "tracking order 'pizza' placed at ") {
+		t.Errorf("the last order should be tracked: %q", r.Out.String())
+	}
+	exp := []string{dawg.StageBake.String(), dawg.StageOutForDelivery.String(), dawg.StageComplete.String()}
+	if stages := trackerStages(r.Out.String()); strings.Join(stages, ",") != strings.Join(exp, ",") {
+		t.Errorf("got stages %v, want %v", stages, exp)
+	}
+}
+
+func TestOrder_Track(t *testing.T) {
+	r := cmdtest.NewTestRecorder(t)
+	defer r.CleanUp()
+	var placed bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("/power/store/4336/profile", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"StoreID": "4336", "Status": 0}`)
+	})
+	mux.HandleFunc("/power/price-order", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Status": 0, "Order": {"OrderID": "1234", "Amounts": {"Customer": 12.99}}}`)
+	})
+	mux.HandleFunc("/power/place-order", func(w http.ResponseWriter, r *http.Request) {
+		placed = true
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"Status": 0, "Order": {"OrderID": "1234", "PulseOrderGuid": "abc-def"}}`)
+	})
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if phone := r.URL.Query().Get("phonenumber"); phone != "5555555555" {
+			t.Errorf("wrong phone number %q", phone)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[{"OrderID": "other", "OrderStatus": "Oven"},
+			{"OrderID": "1234", "PulseOrderGuid": "abc-def", "OrderStatus": "Complete"}]`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c, err := dawg.NewClient(dawg.WithBaseURL(srv.URL), dawg.WithTrackerURL(srv.URL+"/orders"))
+	tests.Check(err)
+
+	addr := dawg.StreetAddrFromAddress(r.Address())
+	raw, err := json.Marshal(&dawg.Order{
+		OrderName:     "pizza",
+		ServiceMethod: dawg.Carryout,
+		StoreID:       "4336",
+		Address:       addr,
+		Products:      []*dawg.OrderProduct{{ItemCommon: dawg.ItemCommon{Code: "14SCREEN"}, Qty: 1}},
+	})
+	tests.Check(err)
+	tests.Check(r.DataBase.Put(data.OrderPrefix+"pizza", raw))
+
+	cmd := NewOrderCmd(r).(*orderCmd)
+	cmd.getaddress = func() dawg.Address { return addr }
+	cmd.newStore, cmd.placeOrder, cmd.newTracker = c.NewStore, c.PlaceOrder, c.NewTracker
+	tests.Check(cmd.Cmd().ParseFlags([]string{"--pay=cash", "--phone=555-555-5555", "--yes", "--track"}))
+	tests.Check(cmd.Run(cmd.Cmd(), []string{"pizza"}))
+	if !placed {
+		t.Fatal("the order was not placed")
+	}
+	if stages := trackerStages(r.Out.String()); len(stages) != 1 || stages[0] != dawg.StageComplete.String() {
+		t.Errorf("the placed order was not tracked: %q", r.Out.String())
+	}
+	last, err := data.GetLastOrder(r.DataBase)
+	tests.Check(err)
+	tests.StrEq(last.TrackingID, "abc-def", "wrong tracking id saved")
+}
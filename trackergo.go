@@ -0,0 +1,316 @@
+package dawg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const trackerPath = "/tracker-presentation-service/v2/orders"
+
+// OrderStage is a step in the process of making and delivering an order.
+type OrderStage int
+
+const (
+	// StageUnknown is used when the tracker sends a status that is not recognized.
+	StageUnknown OrderStage = iota
+	// StagePlaced means the store has received the order.
+	StagePlaced
+	// StagePrep means the order is being made.
+	StagePrep
+	// StageBake means the order is in the oven.
+	StageBake
+	// StageQualityCheck means the order is out of the oven and being checked.
+	StageQualityCheck
+	// StageOutForDelivery means the order has left the store with a driver.
+	StageOutForDelivery
+	// StageComplete means the order has been delivered or picked up.
+	StageComplete
+	// StageCancelled means the order was voided or marked as bad by the
+	// store and will not be completed.
+	StageCancelled
+)
+
+var stageNames = map[OrderStage]string{
+	StageUnknown:        "Unknown",
+	StagePlaced:         "Order Placed",
+	StagePrep:           "Prep",
+	StageBake:           "Bake",
+	StageQualityCheck:   "Quality Check",
+	StageOutForDelivery: "Out for Delivery",
+	StageComplete:       "Complete",
+	StageCancelled:      "Cancelled",
+}
+
+func (s OrderStage) String() string {
+	if name, ok := stageNames[s]; ok {
+		return name
+	}
+	return stageNames[StageUnknown]
+}
+
+// Done returns true if the stage is the last stage for an order, either
+// because it was completed or cancelled.
+func (s OrderStage) Done() bool {
+	return s == StageComplete || s == StageCancelled
+}
+
+// parseStage converts the status sent by the tracker to an OrderStage.
+func parseStage(status string) OrderStage {
+	switch strings.ToLower(strings.TrimSpace(status)) {
+	case "order placed", "placed", "future":
+		return StagePlaced
+	case "prep", "makeline", "making":
+		return StagePrep
+	case "bake", "oven", "baking":
+		return StageBake
+	case "quality check", "rack", "routing station":
+		return StageQualityCheck
+	case "out the door", "out for delivery", "route", "driver":
+		return StageOutForDelivery
+	case "complete", "completed", "delivered":
+		return StageComplete
+	case "bad", "void", "voided", "cancelled", "canceled":
+		return StageCancelled
+	}
+	return StageUnknown
+}
+
+// OrderStatus is the status of a placed order as reported by the dominos
+// order tracker.
+type OrderStatus struct {
+	StoreID          string
+	OrderID          string
+	OrderKey         string
+	PulseOrderGUID   string `json:"PulseOrderGuid"`
+	Phone            string
+	ServiceMethod    string
+	OrderDescription string
+	DriverName       string
+	ManagerName      string
+
+	// Status is the raw status string sent by the tracker, see Stage.
+	Status string `json:"OrderStatus"`
+
+	// Times (formatted by dominos) when the order reached each stage.
+	StartTime    string
+	OvenTime     string
+	RackTime     string
+	RouteTime    string
+	DeliveryTime string
+	AsOfTime     string
+
+	// Stage is the parsed version of Status.
+	Stage OrderStage `json:"-"`
+}
+
+// matches returns true if id is any of the status's order identifiers.
+func (s *OrderStatus) matches(id string) bool {
+	return id == s.OrderID || id == s.OrderKey ||
+		strings.EqualFold(id, s.PulseOrderGUID)
+}
+
+// ErrOrderNotTracked is returned when the tracker has no record of an order.
+var ErrOrderNotTracked = errors.New("could not find the order in the dominos tracker")
+
+// ErrOrderCancelled is returned by Follow when the store cancels an order.
+var ErrOrderCancelled = errors.New("the order was cancelled by the store")
+
+// Tracker follows the status of an order after it has been placed. Orders
+// can be found by phone number, order ID, or both.
+type Tracker struct {
+	// Phone is the phone number the order was placed with.
+	Phone string
+	// OrderID is the order id or the pulse order guid of the order. If
+	// it is empty the most recent order for the phone number is tracked.
+	OrderID string
+	// Interval is how often the tracker is polled by Follow.
+	Interval time.Duration
+
+	cli *client
+}
+
+// DefaultTrackInterval is the default time between tracker updates.
+const DefaultTrackInterval = 30 * time.Second
+
+// NewTracker creates an order tracker. Either the phone number or the order
+// id may be empty but not both.
+func NewTracker(phone, orderID string) *Tracker {
+	return newTracker(orderClient, phone, orderID)
+}
+
+// NewTracker creates an order tracker that uses the client. See the
+// NewTracker function.
+func (c *Client) NewTracker(phone, orderID string) *Tracker {
+	return newTracker(c.cli, phone, orderID)
+}
+
+// Tracker returns a tracker for the order. The order must have been
+// placed with a phone number.
+func (o *Order) Tracker() *Tracker {
+	cli := o.cli
+	if cli == nil {
+		cli = orderClient
+	}
+	return newTracker(cli, o.Phone, o.TrackingID())
+}
+
+func newTracker(c *client, phone, orderID string) *Tracker {
+	return &Tracker{
+		Phone:    strings.Map(digitsOnly, phone),
+		OrderID:  orderID,
+		Interval: DefaultTrackInterval,
+		cli:      c,
+	}
+}
+
+func digitsOnly(r rune) rune {
+	if r >= '0' && r <= '9' {
+		return r
+	}
+	return -1
+}
+
+// Status gets the current status of the order.
+func (t *Tracker) Status() (*OrderStatus, error) {
+	return t.StatusContext(context.Background())
+}
+
+// StatusContext is the same as Status but with a context.
+func (t *Tracker) StatusContext(ctx context.Context) (*OrderStatus, error) {
+	var (
+		statuses []*OrderStatus
+		err      error
+	)
+	switch {
+	case t.Phone != "":
+		err = t.get(ctx, "", url.Values{"phonenumber": {t.Phone}}, &statuses)
+	case t.OrderID != "":
+		status := &OrderStatus{}
+		err = t.get(ctx, "/"+url.PathEscape(t.OrderID), nil, status)
+		statuses = []*OrderStatus{status}
+	default:
+		return nil, errors.New("tracker needs a phone number or an order id")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, s := range statuses {
+		if s == nil {
+			continue
+		}
+		if t.OrderID == "" || s.matches(t.OrderID) {
+			s.Stage = parseStage(s.Status)
+			return s, nil
+		}
+	}
+	return nil, ErrOrderNotTracked
+}
+
+// Follow will poll the tracker until the order is complete or the context
+// is canceled. The function fn is called every time the order moves to a
+// new stage, if it returns an error then Follow will stop and return it.
+// ErrOrderCancelled is returned if the store cancels the order.
+func (t *Tracker) Follow(ctx context.Context, fn func(*OrderStatus) error) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = DefaultTrackInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := OrderStage(-1)
+	for {
+		status, err := t.StatusContext(ctx)
+		if err != nil {
+			return err
+		}
+		if status.Stage != last {
+			last = status.Stage
+			if err = fn(status); err != nil {
+				return err
+			}
+		}
+		if status.Stage == StageCancelled {
+			return ErrOrderCancelled
+		}
+		if status.Stage.Done() {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// TrackerUpdate is sent by Tracker.Watch, only one of the fields will be set.
+type TrackerUpdate struct {
+	Status *OrderStatus
+	Err    error
+}
+
+// Watch is a streaming version of Follow. The returned channel gets a
+// value every time the order changes stage and is closed when the order
+// is complete, the context is canceled, or an error is sent.
+func (t *Tracker) Watch(ctx context.Context) <-chan TrackerUpdate {
+	ch := make(chan TrackerUpdate)
+	go func() {
+		defer close(ch)
+		err := t.Follow(ctx, func(s *OrderStatus) error {
+			select {
+			case ch <- TrackerUpdate{Status: s}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			select {
+			case ch <- TrackerUpdate{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return ch
+}
+
+func (t *Tracker) get(ctx context.Context, path string, params url.Values, v interface{}) error {
+	market := t.cli.getMarket()
+	u := url.URL{Scheme: "https", Host: market.TrackerHost, Path: trackerPath}
+	if t.cli.trackerURL != nil {
+		u = *t.cli.trackerURL
+	}
+	u.Path += path
+	u.RawQuery = params.Encode()
+
+	req := &http.Request{
+		Method: "GET",
+		Host:   u.Host,
+		Proto:  "HTTP/1.1",
+		Header: http.Header{
+			"Accept":        {"application/json"},
+			"DPZ-Language":  {t.cli.language()},
+			"DPZ-Market":    {market.TrackerName},
+			"Cache-Control": {"no-cache"},
+		},
+		URL: &u,
+	}
+	t.cli.setUserAgent(req.Header)
+	b, err := t.cli.do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(b, v); err != nil {
+		return fmt.Errorf("could not decode tracker response: %w", err)
+	}
+	return nil
+}
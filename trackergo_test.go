@@ -0,0 +1,195 @@
+package dawg
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseStage(t *testing.T) {
+	for status, stage := range map[string]OrderStage{
+		"Order Placed":  StagePlaced,
+		"Makeline":      StagePrep,
+		"Oven":          StageBake,
+		"Rack":          StageQualityCheck,
+		"Out the Door":  StageOutForDelivery,
+		" complete ":    StageComplete,
+		"Bad":           StageCancelled,
+		"Void":          StageCancelled,
+		"something new": StageUnknown,
+	} {
+		if s := parseStage(status); s != stage {
+			t.Errorf("parseStage(%q) = %v, want %v", status, s, stage)
+		}
+	}
+	if !StageComplete.Done() || !StageCancelled.Done() || StageOutForDelivery.Done() {
+		t.Error("only the complete and cancelled stages should be done")
+	}
+	if OrderStage(100).String() != "Unknown" {
+		t.Error("bad stages should be unknown")
+	}
+}
+
+func TestTracker(t *testing.T) {
+	statuses := []string{"Order Placed", "Order Placed", "Oven", "Out the Door", "Complete"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orders" {
+			t.Errorf("wrong path %q", r.URL.Path)
+		}
+		if phone := r.URL.Query().Get("phonenumber"); phone != "5555555555" {
+			t.Errorf("wrong phone number %q", phone)
+		}
+		if m := r.Header.Get("DPZ-Market"); m != "UNITED_STATES" {
+			t.Errorf("wrong market header %q", m)
+		}
+		status := statuses[calls]
+		if calls < len(statuses)-1 {
+			calls++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]*OrderStatus{
+			{OrderID: "other", Status: "Complete"},
+			{OrderID: "1234", PulseOrderGUID: "abc-def", Status: status},
+		})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithTrackerURL(srv.URL + "/orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := c.NewTracker("(555) 555-5555", "ABC-DEF")
+	tr.Interval = time.Millisecond
+
+	var stages []OrderStage
+	err = tr.Follow(context.Background(), func(s *OrderStatus) error {
+		if s.OrderID != "1234" {
+			t.Errorf("tracked the wrong order: %q", s.OrderID)
+		}
+		stages = append(stages, s.Stage)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := []OrderStage{StagePlaced, StageBake, StageOutForDelivery, StageComplete}
+	if len(stages) != len(exp) {
+		t.Fatalf("got stages %v, want %v", stages, exp)
+	}
+	for i := range exp {
+		if stages[i] != exp[i] {
+			t.Errorf("stage %d: got %v, want %v", i, stages[i], exp[i])
+		}
+	}
+
+	tr.OrderID = "not-an-order"
+	if _, err = tr.Status(); err != ErrOrderNotTracked {
+		t.Errorf("expected ErrOrderNotTracked, got %v", err)
+	}
+	if _, err = NewTracker("", "").Status(); err == nil {
+		t.Error("expected an error for a tracker with no phone or order id")
+	}
+}
+
+func TestTracker_Cancelled(t *testing.T) {
+	statuses := []string{"Oven", "Void"}
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[calls]
+		if calls < len(statuses)-1 {
+			calls++
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&OrderStatus{OrderID: "1234", Status: status})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithTrackerURL(srv.URL + "/orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := c.NewTracker("", "1234")
+	tr.Interval = time.Millisecond
+
+	var stages []OrderStage
+	err = tr.Follow(context.Background(), func(s *OrderStatus) error {
+		stages = append(stages, s.Stage)
+		return nil
+	})
+	if err != ErrOrderCancelled {
+		t.Errorf("expected ErrOrderCancelled, got %v", err)
+	}
+	if len(stages) != 2 || stages[1] != StageCancelled {
+		t.Errorf("got stages %v, the last one should be cancelled", stages)
+	}
+
+	calls = 0
+	var last TrackerUpdate
+	for u := range tr.Watch(context.Background()) {
+		last = u
+	}
+	if last.Err != ErrOrderCancelled {
+		t.Errorf("Watch should send ErrOrderCancelled, got %v", last.Err)
+	}
+}
+
+func TestTracker_Market(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m := r.Header.Get("DPZ-Market"); m != "CANADA" {
+			t.Errorf("wrong market header %q", m)
+		}
+		if lang := r.Header.Get("DPZ-Language"); lang != "en" {
+			t.Errorf("wrong language header %q", lang)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&OrderStatus{OrderID: "1234", Status: "Prep"})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithMarket(MarketCA), WithTrackerURL(srv.URL+"/orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.NewTracker("", "1234").Status(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestTracker_Watch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/orders/1234" {
+			t.Errorf("wrong path %q", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&OrderStatus{OrderID: "1234", Status: "Prep"})
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithTrackerURL(srv.URL + "/orders"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := c.NewTracker("", "1234")
+	tr.Interval = time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+
+	updates := tr.Watch(ctx)
+	u := <-updates
+	if u.Err != nil {
+		t.Fatal(u.Err)
+	}
+	if u.Status.Stage != StagePrep {
+		t.Errorf("got stage %v, want %v", u.Status.Stage, StagePrep)
+	}
+	cancel()
+	for u = range updates {
+		if u.Err != nil && !errors.Is(u.Err, context.Canceled) {
+			t.Error(u.Err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package dawg
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// AddCard will save a card to the user's dominos account. The nickname is the
+// name that the card is saved with and the billing zip is the zip code of
+// the card's billing address. If isDefault is true then the card becomes the
+// account's default card.
+func (u *UserProfile) AddCard(c Card, nickname, billingZip string, isDefault bool) (*UserCard, error) {
+	return u.AddCardContext(context.Background(), c, nickname, billingZip, isDefault)
+}
+
+// AddCardContext is the same as AddCard but with a context.
+func (u *UserProfile) AddCardContext(ctx context.Context, c Card, nickname, billingZip string, isDefault bool) (*UserCard, error) {
+	if c == nil {
+		return nil, errors.New("cannot save a nil card")
+	}
+	if err := ValidateCard(c); err != nil {
+		return nil, err
+	}
+	if billingZip == "" {
+		return nil, errors.New("saved cards need a billing zip code")
+	}
+	exp := c.ExpiresOn()
+	body := &newUserCard{
+		CardType:        strings.ToUpper(findCardType(c.Num())),
+		Number:          c.Num(),
+		ExpirationMonth: int(exp.Month()),
+		ExpirationYear:  exp.Year(),
+		SecurityCode:    c.Code(),
+		BillingZip:      billingZip,
+		NickName:        nickname,
+		IsDefault:       isDefault,
+	}
+	card := &UserCard{}
+	if err := u.customerRequest(ctx, "POST", "card", body, card); err != nil {
+		return nil, err
+	}
+	card.user = u
+	return card, nil
+}
+
+// UpdateCard will update a saved card's nickname, billing zip, and whether or
+// not it is the account's default card.
+func (u *UserProfile) UpdateCard(c *UserCard) error {
+	return u.UpdateCardContext(context.Background(), c)
+}
+
+// UpdateCardContext is the same as UpdateCard but with a context.
+func (u *UserProfile) UpdateCardContext(ctx context.Context, c *UserCard) error {
+	if c == nil || c.ID == "" {
+		return errors.New("saved card has no card id")
+	}
+	body := &userCardUpdate{
+		NickName:   c.NickName,
+		IsDefault:  c.IsDefault,
+		BillingZip: c.BillingZip,
+	}
+	return u.customerRequest(ctx, "PUT", "card/"+c.ID, body, nil)
+}
+
+// DeleteCard will remove a saved card from the user's account.
+func (u *UserProfile) DeleteCard(c *UserCard) error {
+	return u.DeleteCardContext(context.Background(), c)
+}
+
+// DeleteCardContext is the same as DeleteCard but with a context.
+func (u *UserProfile) DeleteCardContext(ctx context.Context, c *UserCard) error {
+	if c == nil || c.ID == "" {
+		return errors.New("saved card has no card id")
+	}
+	return u.customerRequest(ctx, "DELETE", "card/"+c.ID, nil, nil)
+}
+
+// FindCard gets a saved card by its nickname. Case is ignored when
+// comparing nicknames.
+func (u *UserProfile) FindCard(nickname string) (*UserCard, error) {
+	cards, err := u.Cards()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cards {
+		if strings.EqualFold(c.NickName, nickname) {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("no saved card named '%s'", nickname)
+}
+
+type newUserCard struct {
+	CardType        string `json:"cardType"`
+	Number          string `json:"number"`
+	ExpirationMonth int    `json:"expirationMonth"`
+	ExpirationYear  int    `json:"expirationYear"`
+	SecurityCode    string `json:"securityCode"`
+	BillingZip      string `json:"billingZip"`
+	NickName        string `json:"nickName"`
+	IsDefault       bool   `json:"isDefault"`
+}
+
+type userCardUpdate struct {
+	NickName   string `json:"nickName"`
+	IsDefault  bool   `json:"isDefault"`
+	BillingZip string `json:"billingZip"`
+}
+
+// customerRequest sends a json body to one of the user's customer endpoints.
+// The response is decoded into obj if it is not nil and dominos sent json.
+func (u *UserProfile) customerRequest(ctx context.Context, method, path string, body, obj interface{}) error {
+	if u.ID == "" {
+		return errors.New("UserProfile not fully initialized: needs CustomerID")
+	}
+	var r io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(raw)
+	}
+	endpoint := "/power/customer/" + u.ID
+	if path != "" {
+		endpoint += "/" + path
+	}
+	req := u.cli.newRequest(ctx, method, endpoint, nil, r)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := u.cli.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("dawg: could not %s %s: %s", strings.ToLower(method), endpoint, resp.Status)
+	}
+	if obj == nil || !isjson(resp) {
+		_, err = io.Copy(ioutil.Discard, resp.Body)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(obj)
+}
@@ -0,0 +1,101 @@
+package dawg
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUserCards(t *testing.T) {
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case "GET":
+			fmt.Fprint(w, `[{"id":"1","nickName":"Work","cardType":"VISA","lastFour":"1111"},
+				{"id":"2","nickName":"home","cardType":"MASTERCARD","lastFour":"4444"}]`)
+		case "POST":
+			body := newUserCard{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+			if body.CardType != "VISA" || body.ExpirationMonth != 1 || body.ExpirationYear != 2030 ||
+				body.BillingZip != "12345" || body.SecurityCode != "123" || !body.IsDefault {
+				t.Errorf("wrong card sent: %+v", body)
+			}
+			fmt.Fprintf(w, `{"id":"3","nickName":%q,"lastFour":"1111"}`, body.NickName)
+		case "PUT":
+			body := userCardUpdate{}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				t.Error(err)
+				return
+			}
+			if body.NickName != "office" || !body.IsDefault {
+				t.Errorf("wrong card update: %+v", body)
+			}
+		case "DELETE":
+			w.Header().Set("Content-Type", "text/plain")
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &UserProfile{ID: "user", cli: c.cli}
+
+	saved, err := user.AddCard(NewCard("4111111111111111", "01/30", 123), "new", "12345", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID != "3" || saved.NickName != "new" || saved.user != user {
+		t.Errorf("wrong saved card: %+v", saved)
+	}
+	if _, err = user.AddCard(NewCard("4111111111111111", "01/30", 123), "new", "", false); err == nil {
+		t.Error("expected an error for a card without a billing zip")
+	}
+
+	card, err := user.FindCard("work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.ID != "1" {
+		t.Errorf("found the wrong card: %+v", card)
+	}
+	if _, err = user.FindCard("nothere"); err == nil {
+		t.Error("expected an error for a missing card")
+	}
+	card.NickName = "office"
+	card.IsDefault = true
+	if err = user.UpdateCard(card); err != nil {
+		t.Fatal(err)
+	}
+	if err = user.DeleteCard(card); err != nil {
+		t.Fatal(err)
+	}
+	if err = user.DeleteCard(&UserCard{}); err == nil {
+		t.Error("expected an error for a card without an id")
+	}
+
+	expected := []string{
+		"POST /power/customer/user/card",
+		"GET /power/customer/user/card",
+		"GET /power/customer/user/card",
+		"PUT /power/customer/user/card/1",
+		"DELETE /power/customer/user/card/1",
+	}
+	if len(calls) != len(expected) {
+		t.Fatalf("wrong requests: %v", calls)
+	}
+	for i := range expected {
+		if calls[i] != expected[i] {
+			t.Errorf("request %d: got %q, want %q", i, calls[i], expected[i])
+		}
+	}
+}
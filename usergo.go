@@ -1,25 +1,78 @@
 package dawg
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"net/http"
-	"net/url"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/harrybrwn/apizza/dawg/internal/auth"
 )
 
 // SignIn will create a new UserProfile and sign in the account.
 func SignIn(username, password string) (*UserProfile, error) {
-	err := authorize(orderClient.Client, username, password)
+	return signIn(context.Background(), orderClient, username, password)
+}
+
+// SignInContext is the same as SignIn but the context is used for
+// the authentication and login requests.
+func SignInContext(ctx context.Context, username, password string) (*UserProfile, error) {
+	return signIn(ctx, orderClient, username, password)
+}
+
+func signIn(ctx context.Context, c *client, username, password string) (*UserProfile, error) {
+	// the token is set as the transport of the user's own copy of the
+	// client so that it does not leak into every other request
+	usercli := c.clone()
+	err := authorize(ctx, usercli, username, password)
 	if err != nil {
 		return nil, err
 	}
-	return login(orderClient)
+	return login(ctx, usercli)
+}
+
+// SignInWithToken will create a UserProfile from a Session saved after a
+// previous sign in (see UserProfile.Session) instead of sending the
+// password again. An expired session will be refreshed.
+func SignInWithToken(s *Session) (*UserProfile, error) {
+	return signInWithToken(context.Background(), orderClient, s)
+}
+
+// SignInWithTokenContext is the same as SignInWithToken but with a context.
+func SignInWithTokenContext(ctx context.Context, s *Session) (*UserProfile, error) {
+	return signInWithToken(ctx, orderClient, s)
+}
+
+func signInWithToken(ctx context.Context, c *client, s *Session) (*UserProfile, error) {
+	if s == nil || s.AccessToken == "" {
+		return nil, errors.New("session has no access token")
+	}
+	tok := auth.NewToken()
+	tok.AccessToken = s.AccessToken
+	tok.RefreshToken = s.RefreshToken
+	tok.Type = s.Type
+	tok.Expires = s.Expires
+
+	usercli := c.clone()
+	setToken(usercli, tok)
+	return login(ctx, usercli)
+}
+
+// Session holds the oauth token of a signed in user. It can be stored as
+// json and used later with SignInWithToken.
+type Session struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Type         string    `json:"token_type"`
+	Expires      time.Time `json:"expires"`
 }
 
-// TODO: find out how to update a profile on domino's end
+// Expired returns true if the session's access token has expired.
+func (s *Session) Expired() bool {
+	return !s.Expires.IsZero() && time.Now().After(s.Expires)
+}
 
 // UserProfile is a Dominos user profile.
 type UserProfile struct {
@@ -67,9 +120,27 @@ type UserProfile struct {
 	loyaltyData *CustomerLoyalty
 }
 
-// AddAddress will add an address to the dominos account.
+// Session returns the user's current session. The session will change when
+// the access token is refreshed so it should be saved again after using
+// the UserProfile.
+func (u *UserProfile) Session() (*Session, error) {
+	tok := u.cli.token()
+	if tok == nil {
+		return nil, errors.New("UserProfile is not signed in")
+	}
+	cred := tok.Credentials()
+	return &Session{
+		AccessToken:  cred.AccessToken,
+		RefreshToken: cred.RefreshToken,
+		Type:         cred.Type,
+		Expires:      cred.Expires,
+	}, nil
+}
+
+// AddAddress will add an address to the user's profile. The address is not
+// sent to dominos until the profile is saved, see UserProfile.Save and
+// UserProfile.SaveAddress.
 func (u *UserProfile) AddAddress(a Address) {
-	// TODO: consider sending a request to dominos to update the user with this address.
 	u.Addresses = append(u.Addresses, UserAddressFromAddress(a))
 }
 
@@ -80,30 +151,39 @@ var (
 
 // StoresNearMe will find the stores closest to the user's default address.
 func (u *UserProfile) StoresNearMe() ([]*Store, error) {
+	return u.StoresNearMeContext(context.Background())
+}
+
+// StoresNearMeContext is the same as StoresNearMe but with a context.
+func (u *UserProfile) StoresNearMeContext(ctx context.Context) ([]*Store, error) {
 	if u.ServiceMethod == "" {
 		return nil, errUserNoServiceMethod
 	}
 	if err := u.addressCheck(); err != nil {
 		return nil, err
 	}
-	return asyncNearbyStores(u.cli, u.DefaultAddress(), u.ServiceMethod)
+	return asyncNearbyStores(ctx, u.cli, u.DefaultAddress(), u.ServiceMethod)
 }
 
 // NearestStore will find the the store that is closest to the user's default address.
 func (u *UserProfile) NearestStore(service string) (*Store, error) {
+	return u.NearestStoreContext(context.Background(), service)
+}
+
+// NearestStoreContext is the same as NearestStore but with a context.
+func (u *UserProfile) NearestStoreContext(ctx context.Context, service string) (*Store, error) {
 	var err error
 	if u.store != nil {
 		return u.store, nil
 	}
 
-	// Pass the authorized user's client along to the
-	// store which will use the user's credentials
-	// on each request.
-	c := &client{host: orderHost, Client: u.cli.Client}
 	if err = u.addressCheck(); err != nil {
 		return nil, err
 	}
-	u.store, err = getNearestStore(c, u.DefaultAddress(), service)
+	// Pass the authorized user's client along to the
+	// store which will use the user's credentials
+	// on each request.
+	u.store, err = getNearestStore(ctx, u.cli, u.DefaultAddress(), service)
 	return u.store, err
 }
 
@@ -149,14 +229,30 @@ func (u *UserProfile) SetStore(store *Store) error {
 
 // Cards will get the cards that Dominos has saved in their database. (see UserCard)
 func (u *UserProfile) Cards() ([]*UserCard, error) {
+	return u.CardsContext(context.Background())
+}
+
+// CardsContext is the same as Cards but with a context.
+func (u *UserProfile) CardsContext(ctx context.Context) ([]*UserCard, error) {
 	cards := make([]*UserCard, 0)
-	return cards, u.customerEndpoint(u.cli, "card", nil, &cards)
+	if err := u.customerEndpoint(ctx, u.cli, "card", nil, &cards); err != nil {
+		return nil, err
+	}
+	for _, c := range cards {
+		c.user = u
+	}
+	return cards, nil
 }
 
 // Loyalty returns the user's loyalty meta-data (see CustomerLoyalty)
 func (u *UserProfile) Loyalty() (*CustomerLoyalty, error) {
+	return u.LoyaltyContext(context.Background())
+}
+
+// LoyaltyContext is the same as Loyalty but with a context.
+func (u *UserProfile) LoyaltyContext(ctx context.Context) (*CustomerLoyalty, error) {
 	u.loyaltyData = new(CustomerLoyalty)
-	return u.loyaltyData, u.customerEndpoint(u.cli, "loyalty", nil, u.loyaltyData)
+	return u.loyaltyData, u.customerEndpoint(ctx, u.cli, "loyalty", nil, u.loyaltyData)
 }
 
 // for internal use (caches the loyalty data)
@@ -169,14 +265,27 @@ func (u *UserProfile) getLoyalty() (*CustomerLoyalty, error) {
 
 // PreviousOrders will return `n` of the user's previous orders.
 func (u *UserProfile) PreviousOrders(n int) ([]*EasyOrder, error) {
-	return u.ordersMeta.CustomerOrders, u.initOrdersMeta(n)
+	return u.PreviousOrdersContext(context.Background(), n)
+}
+
+// PreviousOrdersContext is the same as PreviousOrders but with a context.
+func (u *UserProfile) PreviousOrdersContext(ctx context.Context, n int) ([]*EasyOrder, error) {
+	if err := u.initOrdersMeta(ctx, n); err != nil {
+		return nil, err
+	}
+	return u.ordersMeta.CustomerOrders, nil
 }
 
 // GetEasyOrder will return the user's easy order.
 func (u *UserProfile) GetEasyOrder() (*EasyOrder, error) {
+	return u.GetEasyOrderContext(context.Background())
+}
+
+// GetEasyOrderContext is the same as GetEasyOrder but with a context.
+func (u *UserProfile) GetEasyOrderContext(ctx context.Context) (*EasyOrder, error) {
 	var err error
 	if u.ordersMeta == nil {
-		if err = u.initOrdersMeta(3); err != nil {
+		if err = u.initOrdersMeta(ctx, 3); err != nil {
 			return nil, err
 		}
 	}
@@ -184,20 +293,26 @@ func (u *UserProfile) GetEasyOrder() (*EasyOrder, error) {
 }
 
 // Orders returns a variety of meta-data on the user's previous and saved orders.
-func (u *UserProfile) initOrdersMeta(limit int) error {
+func (u *UserProfile) initOrdersMeta(ctx context.Context, limit int) error {
 	u.ordersMeta = &customerOrders{}
 	return u.customerEndpoint(
-		u.cli, "order",
-		Params{"limit": limit, "lang": DefaultLang},
+		ctx, u.cli, "order",
+		Params{"limit": limit, "lang": u.cli.language()},
 		&u.ordersMeta,
 	)
 }
 
 // NewOrder will create a new *dawg.Order struct with all of the user's information.
 func (u *UserProfile) NewOrder() (*Order, error) {
+	return u.NewOrderContext(context.Background())
+}
+
+// NewOrderContext is the same as NewOrder but the context is used if the
+// user's nearest store needs to be found.
+func (u *UserProfile) NewOrderContext(ctx context.Context) (*Order, error) {
 	var err error
 	if u.store == nil {
-		_, err = u.NearestStore(u.ServiceMethod)
+		_, err = u.NearestStoreContext(ctx, u.ServiceMethod)
 		if err != nil {
 			return nil, err
 		}
@@ -206,7 +321,7 @@ func (u *UserProfile) NewOrder() (*Order, error) {
 		FirstName:     u.FirstName,
 		LastName:      u.LastName,
 		Email:         u.Email,
-		LanguageCode:  DefaultLang,
+		LanguageCode:  u.cli.language(),
 		ServiceMethod: u.ServiceMethod,
 		StoreID:       u.store.ID,
 		CustomerID:    u.ID,
@@ -214,6 +329,7 @@ func (u *UserProfile) NewOrder() (*Order, error) {
 		Products:      []*OrderProduct{},
 		Address:       StreetAddrFromAddress(u.store.userAddress),
 		Payments:      []*orderPayment{},
+		Coupons:       []*OrderCoupon{},
 		cli:           u.cli,
 	}
 	return order, nil
@@ -237,7 +353,8 @@ func (u *UserProfile) serviceCheck() error {
 }
 
 func (u *UserProfile) customerEndpoint(
-	d doer,
+	ctx context.Context,
+	c *client,
 	path string,
 	params Params,
 	obj interface{},
@@ -250,17 +367,11 @@ func (u *UserProfile) customerEndpoint(
 	}
 	params["_"] = time.Now().Nanosecond()
 
-	return dojson(d, obj, &http.Request{
-		Method: "GET",
-		Proto:  "HTTP/1.1",
-		Header: make(http.Header),
-		URL: &url.URL{
-			Scheme:   "https",
-			Host:     orderHost,
-			Path:     fmt.Sprintf("/power/customer/%s/%s", u.ID, path),
-			RawQuery: params.Encode(),
-		},
-	})
+	return c.dojson(obj, c.newRequest(
+		ctx, "GET",
+		fmt.Sprintf("/power/customer/%s/%s", u.ID, path),
+		params, nil,
+	))
 }
 
 // UserAddress is an address that is saved by dominos and returned when
@@ -302,7 +413,11 @@ type UserAddress struct {
 	Coordinates     map[string]float32
 }
 
-var _ Address = (*UserAddress)(nil)
+var (
+	_ Address     = (*UserAddress)(nil)
+	_ UnitAddress = (*UserAddress)(nil)
+	_ GeoAddress  = (*UserAddress)(nil)
+)
 
 // UserAddressFromAddress converts an address to a UserAddress.
 func UserAddressFromAddress(a Address) *UserAddress {
@@ -324,7 +439,7 @@ func UserAddressFromAddress(a Address) *UserAddress {
 		return addr
 	}
 
-	return &UserAddress{
+	addr := &UserAddress{
 		Street:       a.LineOne(),
 		StreetNumber: streetNum,
 		StreetName:   streetName,
@@ -332,6 +447,11 @@ func UserAddressFromAddress(a Address) *UserAddress {
 		PostalCode:   a.Zip(),
 		Region:       a.StateCode(),
 	}
+	if u, ok := a.(UnitAddress); ok {
+		addr.UnitType, addr.UnitNumber = u.Unit()
+		addr.DeliveryInstructions = u.Instructions()
+	}
+	return addr
 }
 
 // LineOne returns the first line of the address.
@@ -357,6 +477,26 @@ func (ua *UserAddress) Zip() string {
 	return ua.PostalCode
 }
 
+// LatLong returns the coordinates of the address if dominos has them.
+func (ua *UserAddress) LatLong() (lat, long float64, ok bool) {
+	lat32, latok := ua.Coordinates["Latitude"]
+	long32, longok := ua.Coordinates["Longitude"]
+	if !latok || !longok {
+		return 0, 0, false
+	}
+	return float64(lat32), float64(long32), true
+}
+
+// Unit returns the unit type and number of the address.
+func (ua *UserAddress) Unit() (typ, number string) {
+	return ua.UnitType, ua.UnitNumber
+}
+
+// Instructions returns the address' delivery instructions.
+func (ua *UserAddress) Instructions() string {
+	return ua.DeliveryInstructions
+}
+
 // UserCard holds the card data that Dominos stores and send back to users.
 // For security reasons, Dominos does not send the raw card number or the
 // raw security code. Insted they send a card ID that is used to reference
@@ -385,6 +525,8 @@ type UserCard struct {
 
 	CardType   string `json:"cardType"`
 	BillingZip string `json:"billingZip"`
+
+	user *UserProfile
 }
 
 // CustomerLoyalty is a struct that holds account meta-data used by
@@ -401,12 +543,7 @@ type CustomerLoyalty struct {
 	VestedPointBalance int
 	// This is a list of possible coupons that a
 	// customer can receive.
-	LoyaltyCoupons []struct {
-		CouponCode    string
-		PointValue    int
-		BaseCoupon    bool
-		LimitPerOrder string
-	}
+	LoyaltyCoupons []LoyaltyCoupon
 }
 
 // TODO: figure out how the dominos website sends an easy order to the servers
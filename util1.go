@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/harrybrwn/apizza/dawg"
@@ -25,11 +26,12 @@ func YesOrNo(in *os.File, msg string) bool {
 	return false
 }
 
-// AddTopping parses and adds a topping from the raw string.
+// AddTopping parses and adds a topping from the raw string. If the menu is
+// not nil then the topping is checked against the menu before it is added.
 //
 // formated as <name>:<side>:<amount>
 // name is the only one that is required.
-func AddTopping(topStr string, p dawg.Item) error {
+func AddTopping(topStr string, p dawg.Item, m *dawg.Menu) error {
 	var side, amount string
 
 	topping := strings.Split(topStr, ":")
@@ -68,5 +70,65 @@ func AddTopping(topStr string, p dawg.Item) error {
 	default:
 		return errors.New("invalid topping amount, should be any of '0.5', '1.0', '1.5', or '2.0'")
 	}
+	if m != nil {
+		if err := m.ValidateTopping(p, topping[0], side, amount); err != nil {
+			return err
+		}
+	}
 	return p.AddTopping(topping[0], side, amount)
 }
+
+// FindProduct finds an item on the menu that can be added to an order. The
+// code can be a variant or a pre-configured product. The product, size, and
+// crust can also be given instead of a code using the format
+// <product>:<size>:<crust> (ie. "pizza:large:thin"), the crust is optional.
+func FindProduct(m *dawg.Menu, code string) (dawg.Item, error) {
+	if strings.Contains(code, ":") {
+		parts := strings.Split(code, ":")
+		if len(parts) > 3 {
+			return nil, fmt.Errorf("'%s' should be formatted as <product>:<size>:<crust>", code)
+		}
+		parts = append(parts, "")
+		return m.FindVariant(parts[0], parts[1], parts[2])
+	}
+	v, err := m.GetVariant(code)
+	if err == nil {
+		return v, nil
+	}
+	if pc, e := m.GetPreconfigured(code); e == nil {
+		return pc, nil
+	}
+	return nil, err
+}
+
+// SidePrefix is the prefix used to tell AddSide apart from AddTopping when
+// parsing a list of item options.
+const SidePrefix = "side:"
+
+// IsSide returns true if the raw string given is formatted as a side.
+func IsSide(s string) bool {
+	return strings.HasPrefix(strings.ToLower(s), SidePrefix)
+}
+
+// AddSide parses and adds a side from the raw string.
+//
+// formated as side:<code>:<quantity>
+// the quantity is optional and defaults to 1.
+func AddSide(sideStr string, p dawg.SideItem) error {
+	if !IsSide(sideStr) {
+		return errors.New("sides should start with 'side:'")
+	}
+	side := strings.Split(sideStr[len(SidePrefix):], ":")
+	if side[0] == "" || len(side) > 2 {
+		return errors.New("incorrect side format")
+	}
+	qty := 1
+	if len(side) == 2 {
+		n, err := strconv.Atoi(side[1])
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid side quantity '%s'", side[1])
+		}
+		qty = n
+	}
+	return p.AddSide(side[0], qty)
+}
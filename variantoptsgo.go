@@ -0,0 +1,193 @@
+package dawg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// VariantOption is a size or a flavor that a variant can have. Flavors are
+// things like pizza crusts or whether pasta comes in a dish or a bread bowl.
+type VariantOption struct {
+	Code        string
+	Name        string
+	Description string
+	Local       bool
+	SortSeq     string
+}
+
+// ShortDescription returns the short description of a product or variant. It
+// will be empty if the menu does not have one.
+func (m *Menu) ShortDescription(code string) string {
+	return m.ShortDescriptions[code].Description
+}
+
+// FindVariant finds the variant of a product that comes in a size and
+// flavor. The product can be given by its code or name ("S_PIZZA" or
+// "pizza") and the size and flavor can be given by code or name ("14" or
+// "large", "THIN" or "thin"). Case is ignored.
+//
+// An empty flavor will choose the product's first flavor on the menu. An
+// empty size can only be used if the product comes in one size.
+func (m *Menu) FindVariant(product, size, flavor string) (*Variant, error) {
+	p := m.findProductByName(product)
+	if p == nil {
+		return nil, fmt.Errorf("could not find product '%s'", product)
+	}
+	variants := make([]*Variant, 0, len(p.Variants))
+	for _, code := range p.Variants {
+		if v, ok := m.Variants[code]; ok {
+			variants = append(variants, v)
+		}
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("%s has no variants on the menu", p.Name)
+	}
+
+	sizes := variantOptions(m.Sizes[p.ProductType], variants, func(v *Variant) string { return v.SizeCode })
+	flavors := variantOptions(m.Flavors[p.ProductType], variants, func(v *Variant) string { return v.FlavorCode })
+
+	var sz, fl *VariantOption
+	switch {
+	case size != "":
+		if sz = matchVariantOption(size, sizes); sz == nil {
+			return nil, fmt.Errorf("%s does not come in size '%s' (sizes: %s)", p.Name, size, optionNames(sizes))
+		}
+	case len(sizes) > 1:
+		return nil, fmt.Errorf("%s comes in more than one size, choose one of: %s", p.Name, optionNames(sizes))
+	}
+	if flavor != "" {
+		if fl = matchVariantOption(flavor, flavors); fl == nil {
+			return nil, fmt.Errorf("%s does not come in flavor '%s' (flavors: %s)", p.Name, flavor, optionNames(flavors))
+		}
+	}
+
+	var found *Variant
+	for _, f := range flavors {
+		if fl != nil && f.Code != fl.Code {
+			continue
+		}
+		for _, v := range variants {
+			if v.FlavorCode == f.Code && (sz == nil || v.SizeCode == sz.Code) {
+				found = v
+				break
+			}
+		}
+		if found != nil {
+			break
+		}
+	}
+	if found == nil {
+		// only happens when both the size and flavor are given
+		var available []VariantOption
+		for _, f := range flavors {
+			for _, v := range variants {
+				if v.FlavorCode == f.Code && v.SizeCode == sz.Code {
+					available = append(available, f)
+					break
+				}
+			}
+		}
+		return nil, fmt.Errorf("%s does not come in %s %s (flavors in that size: %s)",
+			p.Name, sz.Name, fl.Name, optionNames(available))
+	}
+	return m.initVariant(found), nil
+}
+
+// findProductByName finds a product by its code or name.
+func (m *Menu) findProductByName(name string) *Product {
+	if p, ok := m.Products[name]; ok {
+		return p
+	}
+	codes := make([]string, 0, len(m.Products))
+	for code := range m.Products {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		p := m.Products[code]
+		if strings.EqualFold(code, name) || strings.EqualFold(p.Name, name) {
+			return p
+		}
+	}
+	return nil
+}
+
+// variantOptions returns the options that a list of variants use in the
+// order that the menu sorts them. Options that are not described by the menu
+// only have a code.
+func variantOptions(described map[string]VariantOption, variants []*Variant, code func(*Variant) string) []VariantOption {
+	seen := make(map[string]bool)
+	opts := make([]VariantOption, 0)
+	for _, v := range variants {
+		c := code(v)
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		opt, ok := described[c]
+		if !ok {
+			opt = VariantOption{Code: c, Name: c}
+		}
+		opts = append(opts, opt)
+	}
+	sort.SliceStable(opts, func(i, j int) bool {
+		return opts[i].SortSeq < opts[j].SortSeq
+	})
+	return opts
+}
+
+// matchVariantOption finds the option that matches a code or name. A name
+// matches if it is the whole name, the name without the part in parentheses
+// ("large" for `Large (14")`), or if all of its words are in the name ("thin"
+// for "Crunchy Thin Crust"). Nothing is returned if more than one option
+// matches only by words.
+func matchVariantOption(query string, opts []VariantOption) *VariantOption {
+	q := strings.ToLower(strings.TrimSpace(query))
+	for i, o := range opts {
+		name := strings.ToLower(o.Name)
+		if q == strings.ToLower(o.Code) || q == name {
+			return &opts[i]
+		}
+		if paren := strings.Index(name, "("); paren > 0 && q == strings.TrimSpace(name[:paren]) {
+			return &opts[i]
+		}
+	}
+	var found *VariantOption
+	for i, o := range opts {
+		if containsWords(searchWords(o.Name), searchWords(q)) {
+			if found != nil {
+				return nil
+			}
+			found = &opts[i]
+		}
+	}
+	return found
+}
+
+func containsWords(words, sub []string) bool {
+	if len(sub) == 0 {
+		return false
+	}
+	for _, s := range sub {
+		ok := false
+		for _, w := range words {
+			if w == s {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func optionNames(opts []VariantOption) string {
+	names := make([]string, len(opts))
+	for i, o := range opts {
+		names[i] = o.Name
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,60 @@
+package dawg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMenu_FindVariant(t *testing.T) {
+	menu := menuFromFile(t)
+	if len(menu.Sizes["Pizza"]) == 0 || len(menu.Flavors["Pizza"]) == 0 {
+		t.Fatal("sizes and flavors were not decoded")
+	}
+	if menu.Flavors["Pizza"]["THIN"].Name != "Crunchy Thin Crust" {
+		t.Error("wrong flavor name")
+	}
+	if menu.ShortDescription("B8PCGT") == "" {
+		t.Error("short description was not decoded")
+	}
+
+	for _, tc := range []struct {
+		product, size, flavor string
+		code                  string
+	}{
+		{"pizza", "large", "thin", "14THIN"},
+		{"S_PIZZA", "14", "THIN", "14THIN"},
+		{"Pizza", "Large", "", "14SCREEN"},
+		{"pizza", "x-large", "", "P16IBKZA"},
+		{"pizza", "medium", "pan", "P12IPAZA"},
+		{"pizza", "small", "gluten free", "P10IGFZA"},
+	} {
+		v, err := menu.FindVariant(tc.product, tc.size, tc.flavor)
+		if err != nil {
+			t.Errorf("%s:%s:%s: %v", tc.product, tc.size, tc.flavor, err)
+			continue
+		}
+		if v.Code != tc.code {
+			t.Errorf("%s:%s:%s: expected %s, got %s", tc.product, tc.size, tc.flavor, tc.code, v.Code)
+		}
+		if v.product == nil {
+			t.Error("variant should be initialized")
+		}
+	}
+
+	for _, tc := range []struct {
+		product, size, flavor string
+		err                   string
+	}{
+		{"not a product", "large", "", "could not find product"},
+		{"pizza", "huge", "", "does not come in size 'huge'"},
+		{"pizza", "", "", "more than one size"},
+		{"pizza", "large", "deep dish", "does not come in flavor 'deep dish'"},
+		{"pizza", "large", "crust", "does not come in flavor 'crust'"}, // ambiguous
+		{"pizza", "x-large", "thin", "flavors in that size: Brooklyn Style"},
+	} {
+		_, err := menu.FindVariant(tc.product, tc.size, tc.flavor)
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("%s:%s:%s: expected an error with %q, got %v", tc.product, tc.size, tc.flavor, tc.err, err)
+		}
+	}
+}